@@ -0,0 +1,12 @@
+data "keytos_ezca_issuance_policy" "example" {
+  authority_id     = var.authority_id
+  template_id      = var.template_id
+  certificates_pem = [keytos_ezca_ssl_leaf_cert.example.cert_pem]
+}
+
+check "issuance_policy" {
+  assert {
+    condition     = data.keytos_ezca_issuance_policy.example.compliant
+    error_message = join("\n", data.keytos_ezca_issuance_policy.example.violations[*].message)
+  }
+}
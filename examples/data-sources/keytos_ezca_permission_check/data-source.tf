@@ -0,0 +1,12 @@
+data "keytos_ezca_permission_check" "example" {
+  authority_id = var.authority_id
+  template_id  = var.template_id
+  operation    = "sign"
+
+  lifecycle {
+    postcondition {
+      condition     = self.authorized
+      error_message = self.message
+    }
+  }
+}
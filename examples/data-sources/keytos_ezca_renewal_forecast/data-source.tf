@@ -0,0 +1,14 @@
+data "keytos_ezca_renewal_forecast" "example" {
+  certificates = [
+    for name, cert in keytos_ezca_ssl_leaf_cert.example : {
+      name                 = "keytos_ezca_ssl_leaf_cert.example[\"${name}\"]"
+      validity_not_after   = cert.validity_not_after
+      early_renewal_period = cert.early_renewal_period
+    }
+  ]
+  within_days = 14
+}
+
+output "upcoming_renewals" {
+  value = data.keytos_ezca_renewal_forecast.example.renewals
+}
@@ -0,0 +1,10 @@
+resource "keytos_ezca_cert_pair" "example" {
+  authority_id               = var.authority_id
+  server_template_id         = var.server_template_id
+  client_template_id         = var.client_template_id
+  cert_request_pem           = file("cert_request.pem")
+  validity_period            = "336h" # 14d
+  overwrite_subject_name_str = "CN=node-1,O=Keytos"
+  dns_names                  = ["node-1.example.com"]
+  early_renewal_period       = "24h" # 1d
+}
@@ -0,0 +1,11 @@
+resource "keytos_ezca_code_signing_cert" "example" {
+  authority_id     = var.authority_id
+  template_id      = var.template_id
+  cert_request_pem = file("code_signing_request.pem")
+  validity_period  = "8760h" # 365d
+  overwrite_subject_name = {
+    common_name  = "Keytos Build Pipeline"
+    organization = "Keytos"
+  }
+  early_renewal_period = "720h" # 30d
+}
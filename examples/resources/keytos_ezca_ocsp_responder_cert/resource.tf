@@ -0,0 +1,10 @@
+resource "keytos_ezca_ocsp_responder_cert" "example" {
+  authority_id     = var.authority_id
+  template_id      = var.template_id
+  cert_request_pem = file("ocsp_responder_request.pem")
+  validity_period  = "168h" # 7d
+  overwrite_subject_name = {
+    common_name = "Keytos OCSP Responder"
+  }
+  early_renewal_period = "48h" # 2d
+}
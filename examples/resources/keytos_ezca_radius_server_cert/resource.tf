@@ -0,0 +1,13 @@
+resource "keytos_ezca_radius_server_cert" "example" {
+  authority_id     = var.authority_id
+  template_id      = var.template_id
+  cert_request_pem = file("nps_request.pem")
+  validity_period  = "8760h" # 365d
+  overwrite_subject_name = {
+    common_name = "nps.corp.example.com"
+  }
+  additional_subject_alternative_names = {
+    dns_names = ["nps.corp.example.com"]
+  }
+  early_renewal_period = "720h" # 30d
+}
@@ -0,0 +1,14 @@
+resource "keytos_ezca_ssl_cert" "example" {
+  authority_id    = var.authority_id
+  template_id     = var.template_id
+  key_algorithm   = "ECDSA-P256"
+  validity_period = "336h" # 14d
+  overwrite_subject_name = {
+    common_name  = "example.com"
+    organization = ["Example Inc"]
+  }
+  additional_subject_alternative_names = {
+    dns_names = ["example.com"]
+  }
+  early_renewal_period = "24h" # 1d
+}
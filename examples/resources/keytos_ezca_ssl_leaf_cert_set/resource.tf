@@ -0,0 +1,10 @@
+resource "keytos_ezca_ssl_leaf_cert_set" "example" {
+  authority_id = var.authority_id
+  template_id  = var.template_id
+  cert_requests = {
+    for name in var.node_names : name => file("${name}.csr")
+  }
+  validity_period      = "720h" # 30d
+  early_renewal_period = "168h" # 7d
+  parallelism          = 20
+}
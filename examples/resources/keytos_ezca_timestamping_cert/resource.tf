@@ -0,0 +1,11 @@
+resource "keytos_ezca_timestamping_cert" "example" {
+  authority_id     = var.authority_id
+  template_id      = var.template_id
+  cert_request_pem = file("tsa_request.pem")
+  validity_period  = "43800h" # 5y
+  overwrite_subject_name = {
+    common_name  = "Keytos Timestamp Authority"
+    organization = "Keytos"
+  }
+  early_renewal_period = "8760h" # 1y
+}
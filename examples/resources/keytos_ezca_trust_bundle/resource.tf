@@ -0,0 +1,7 @@
+resource "keytos_ezca_trust_bundle" "example" {
+  certificates_pem = concat(
+    keytos_ezca_ssl_leaf_cert.web.ca_chain_pem,
+    keytos_ezca_ssl_leaf_cert.internal.ca_chain_pem,
+  )
+  pkcs12_password = var.truststore_password
+}
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"math/bits"
+	"slices"
+
+	"github.com/markeytos/ezca-go"
+)
+
+var oidExtensionKeyUsage = asn1.ObjectIdentifier{2, 5, 29, 15}
+
+// certificateRequestUsages returns the key usages and extended key usages
+// requested in the extensionRequest attribute of the PEM encoded certificate
+// request, among those EZCA accepts. Requests that cannot be parsed have no
+// usages, their errors are raised when signing them.
+func certificateRequestUsages(csrPEM string) ([]ezca.KeyUsage, []ezca.ExtKeyUsage) {
+	der, err := csr(csrPEM)
+	if err != nil {
+		return nil, nil
+	}
+	req, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, nil
+	}
+
+	var kus []ezca.KeyUsage
+	for _, ext := range req.Extensions {
+		if !ext.Id.Equal(oidExtensionKeyUsage) {
+			continue
+		}
+		var bs asn1.BitString
+		if _, err := asn1.Unmarshal(ext.Value, &bs); err != nil {
+			continue
+		}
+		for _, ku := range keyUsages {
+			if bit, ok := x509KeyUsages[ezca.KeyUsage(ku)]; ok && keyUsageBitSet(bs, bit) {
+				kus = append(kus, ezca.KeyUsage(ku))
+			}
+		}
+	}
+
+	var ekus []ezca.ExtKeyUsage
+	for _, oid := range extendedKeyUsageOIDs(req.Extensions) {
+		if slices.Contains(extendedKeyUsages, oid) && !slices.Contains(ekus, ezca.ExtKeyUsage(oid)) {
+			ekus = append(ekus, ezca.ExtKeyUsage(oid))
+		}
+	}
+	return kus, ekus
+}
+
+// keyUsageBitSet reports whether the key usage is set in the bit string of a
+// key usage extension, whose first bit is the digital signature usage.
+func keyUsageBitSet(s asn1.BitString, ku x509.KeyUsage) bool {
+	return s.At(bits.TrailingZeros(uint(ku))) != 0
+}
@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"testing"
+
+	"github.com/markeytos/ezca-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateRequestUsages(t *testing.T) {
+	kus, ekus := certificateRequestUsages(testCSR)
+	require.Empty(t, kus)
+	require.Empty(t, ekus)
+
+	csrPEM := testCertificateRequestWithUsagesPEM(t)
+	kus, ekus = certificateRequestUsages(csrPEM)
+	require.Equal(t, []ezca.KeyUsage{ezca.KeyUsageDigitalSignature, ezca.KeyUsageKeyAgreement}, kus)
+	require.Equal(t, []ezca.ExtKeyUsage{ezca.ExtKeyUsageClientAuth}, ekus)
+
+	kus, ekus = certificateRequestUsages("not a request")
+	require.Empty(t, kus)
+	require.Empty(t, ekus)
+}
+
+// testCertificateRequestWithUsagesPEM returns a certificate request for the
+// digital signature, key agreement and certificate signing key usages, and
+// the client authentication and an unknown extended key usage.
+func testCertificateRequestWithUsagesPEM(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	// Bits 0, 4 and 5, the first bit being the most significant one
+	kuValue, err := asn1.Marshal(asn1.BitString{Bytes: []byte{0x8c}, BitLength: 6})
+	require.NoError(t, err)
+	ekuValue, err := asn1.Marshal([]asn1.ObjectIdentifier{{1, 3, 6, 1, 5, 5, 7, 3, 2}, {1, 2, 3, 4}})
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		ExtraExtensions: []pkix.Extension{
+			{Id: oidExtensionKeyUsage, Critical: true, Value: kuValue},
+			{Id: oidExtensionExtendedKeyUsage, Value: ekuValue},
+		},
+	}, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
@@ -0,0 +1,364 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// distinguishedNameAttributeTypes maps the attribute type names accepted in
+// distinguished names, in upper case, to their object identifiers.
+var distinguishedNameAttributeTypes = map[string]string{
+	"CN":           "2.5.4.3",
+	"SERIALNUMBER": "2.5.4.5",
+	"C":            "2.5.4.6",
+	"L":            "2.5.4.7",
+	"ST":           "2.5.4.8",
+	"S":            "2.5.4.8",
+	"STREET":       "2.5.4.9",
+	"O":            "2.5.4.10",
+	"OU":           "2.5.4.11",
+	"POSTALCODE":   "2.5.4.17",
+	"DC":           "0.9.2342.19200300.100.1.25",
+	"UID":          "0.9.2342.19200300.100.1.1",
+	"E":            "1.2.840.113549.1.9.1",
+	"EMAILADDRESS": "1.2.840.113549.1.9.1",
+}
+
+// distinguishedNameAttributeNames maps object identifiers to the attribute
+// type names of canonical distinguished names.
+var distinguishedNameAttributeNames = map[string]string{
+	"2.5.4.3":                    "CN",
+	"2.5.4.5":                    "SERIALNUMBER",
+	"2.5.4.6":                    "C",
+	"2.5.4.7":                    "L",
+	"2.5.4.8":                    "ST",
+	"2.5.4.9":                    "STREET",
+	"2.5.4.10":                   "O",
+	"2.5.4.11":                   "OU",
+	"2.5.4.17":                   "POSTALCODE",
+	"0.9.2342.19200300.100.1.25": "DC",
+	"0.9.2342.19200300.100.1.1":  "UID",
+	"1.2.840.113549.1.9.1":       "E",
+}
+
+var numericOIDRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)+$`)
+
+// parseDistinguishedName parses an RFC 4514 distinguished name string, such
+// as "CN=www.example.com,O=Example,C=US". Spaces around separators are
+// ignored. The relative distinguished names are returned in the order of the
+// RDNSequence, which is the reverse of the string.
+func parseDistinguishedName(s string) (pkix.RDNSequence, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, errors.New("distinguished name is empty")
+	}
+
+	var rdns []pkix.RelativeDistinguishedNameSET
+	var rdn pkix.RelativeDistinguishedNameSET
+	for i := 0; ; {
+		eq := strings.IndexByte(s[i:], '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("missing '=' after attribute type %q", strings.TrimSpace(s[i:]))
+		}
+		oid, err := parseDistinguishedNameAttributeType(strings.TrimSpace(s[i : i+eq]))
+		if err != nil {
+			return nil, err
+		}
+		value, n, err := parseDistinguishedNameValue(s[i+eq+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of attribute %s: %w", strings.TrimSpace(s[i:i+eq]), err)
+		}
+		rdn = append(rdn, pkix.AttributeTypeAndValue{Type: oid, Value: value})
+		i += eq + 1 + n
+
+		if i == len(s) {
+			rdns = append(rdns, rdn)
+			break
+		}
+		if s[i] == ',' {
+			rdns = append(rdns, rdn)
+			rdn = nil
+		}
+		i++
+	}
+
+	slices.Reverse(rdns)
+	return pkix.RDNSequence(rdns), nil
+}
+
+// parseDistinguishedNameAttributeType parses an attribute type name or
+// numeric object identifier.
+func parseDistinguishedNameAttributeType(t string) (asn1.ObjectIdentifier, error) {
+	dotted, ok := distinguishedNameAttributeTypes[strings.ToUpper(t)]
+	if !ok {
+		if !numericOIDRegexp.MatchString(t) {
+			return nil, fmt.Errorf("unknown attribute type %q", t)
+		}
+		dotted = t
+	}
+	var oid asn1.ObjectIdentifier
+	for _, arc := range strings.Split(dotted, ".") {
+		var v int
+		if _, err := fmt.Sscan(arc, &v); err != nil {
+			return nil, fmt.Errorf("invalid attribute type %q: %w", t, err)
+		}
+		oid = append(oid, v)
+	}
+	return oid, nil
+}
+
+// parseDistinguishedNameValue parses the attribute value at the start of s,
+// up to the next unescaped ',' or '+', and returns it with the number of
+// bytes read, not including the separator.
+func parseDistinguishedNameValue(s string) (string, int, error) {
+	i := 0
+	for i < len(s) && s[i] == ' ' {
+		i++
+	}
+	if i < len(s) && s[i] == '#' {
+		end := i + 1
+		for end < len(s) && s[end] != ',' && s[end] != '+' && s[end] != ' ' {
+			end++
+		}
+		value, err := parseDistinguishedNameHexValue(s[i+1 : end])
+		if err != nil {
+			return "", 0, err
+		}
+		for end < len(s) && s[end] == ' ' {
+			end++
+		}
+		if end < len(s) && s[end] != ',' && s[end] != '+' {
+			return "", 0, fmt.Errorf("unexpected %q after hex string", s[end])
+		}
+		return value, end, nil
+	}
+
+	var b []byte
+	// Unescaped trailing spaces are not part of the value
+	trimmed := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case ',', '+':
+			return string(b[:trimmed]), i, nil
+		case '\\':
+			if i+1 == len(s) {
+				return "", 0, errors.New("trailing '\\'")
+			}
+			if strings.IndexByte(` "#+,;<=>\`, s[i+1]) >= 0 {
+				b = append(b, s[i+1])
+				i++
+			} else if i+2 < len(s) && isHexDigit(s[i+1]) && isHexDigit(s[i+2]) {
+				v, _ := hex.DecodeString(s[i+1 : i+3])
+				b = append(b, v[0])
+				i += 2
+			} else {
+				return "", 0, fmt.Errorf("invalid escape sequence at %q", s[i:])
+			}
+			trimmed = len(b)
+		case '"', ';', '<', '>':
+			return "", 0, fmt.Errorf("character %q must be escaped", c)
+		default:
+			b = append(b, c)
+			if c != ' ' {
+				trimmed = len(b)
+			}
+		}
+	}
+	return string(b[:trimmed]), i, nil
+}
+
+// parseDistinguishedNameHexValue parses the hex encoded BER value of a '#'
+// attribute value, which must be a string.
+func parseDistinguishedNameHexValue(h string) (string, error) {
+	der, err := hex.DecodeString(h)
+	if err != nil {
+		return "", fmt.Errorf("invalid hex string: %w", err)
+	}
+	var raw asn1.RawValue
+	rest, err := asn1.Unmarshal(der, &raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid hex string: %w", err)
+	}
+	if len(rest) > 0 {
+		return "", errors.New("invalid hex string: trailing data")
+	}
+	switch raw.Tag {
+	case asn1.TagUTF8String, asn1.TagPrintableString, asn1.TagIA5String, asn1.TagT61String:
+		if raw.Class == asn1.ClassUniversal {
+			return string(raw.Bytes), nil
+		}
+	}
+	return "", errors.New("hex string is not a string value")
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// formatDistinguishedName returns the canonical string of a distinguished
+// name: upper case attribute type names, no spaces around separators,
+// minimal escaping and the attributes of multi-valued relative
+// distinguished names sorted. The order of the relative distinguished names
+// is significant and kept.
+func formatDistinguishedName(rdns pkix.RDNSequence) string {
+	parts := make([]string, 0, len(rdns))
+	for i := len(rdns) - 1; i >= 0; i-- {
+		attrs := make([]string, 0, len(rdns[i]))
+		for _, atv := range rdns[i] {
+			name, ok := distinguishedNameAttributeNames[atv.Type.String()]
+			if !ok {
+				name = atv.Type.String()
+			}
+			attrs = append(attrs, name+"="+escapeDistinguishedNameValue(fmt.Sprint(atv.Value)))
+		}
+		slices.Sort(attrs)
+		parts = append(parts, strings.Join(attrs, "+"))
+	}
+	return strings.Join(parts, ",")
+}
+
+// escapeDistinguishedNameValue escapes the characters RFC 4514 requires to
+// be escaped in attribute values.
+func escapeDistinguishedNameValue(v string) string {
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case strings.IndexByte(`"+,;<>\`, c) >= 0,
+			i == 0 && (c == ' ' || c == '#'),
+			i == len(v)-1 && c == ' ':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == 0:
+			b.WriteString(`\00`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// canonicalDistinguishedName parses and formats a distinguished name string.
+func canonicalDistinguishedName(s string) (string, error) {
+	rdns, err := parseDistinguishedName(s)
+	if err != nil {
+		return "", err
+	}
+	return formatDistinguishedName(rdns), nil
+}
+
+var _ basetypes.StringTypable = distinguishedNameType{}
+
+// distinguishedNameType is a string attribute type holding an RFC 4514
+// distinguished name. Values with the same canonical form are semantically
+// equal, so that cosmetic changes such as spacing or the case of attribute
+// type names plan no change.
+type distinguishedNameType struct {
+	basetypes.StringType
+}
+
+func (t distinguishedNameType) String() string {
+	return "distinguishedNameType"
+}
+
+func (t distinguishedNameType) Equal(o attr.Type) bool {
+	_, ok := o.(distinguishedNameType)
+	return ok
+}
+
+func (t distinguishedNameType) ValueType(ctx context.Context) attr.Value {
+	return distinguishedNameValue{}
+}
+
+func (t distinguishedNameType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return distinguishedNameValue{StringValue: in}, nil
+}
+
+func (t distinguishedNameType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	v, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", v)
+	}
+	return distinguishedNameValue{StringValue: s}, nil
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = distinguishedNameValue{}
+var _ xattr.ValidateableAttribute = distinguishedNameValue{}
+
+// distinguishedNameValue is a value of distinguishedNameType.
+type distinguishedNameValue struct {
+	basetypes.StringValue
+}
+
+func distinguishedNameNull() distinguishedNameValue {
+	return distinguishedNameValue{StringValue: basetypes.NewStringNull()}
+}
+
+func distinguishedNameUnknown() distinguishedNameValue {
+	return distinguishedNameValue{StringValue: basetypes.NewStringUnknown()}
+}
+
+func (v distinguishedNameValue) Type(ctx context.Context) attr.Type {
+	return distinguishedNameType{}
+}
+
+func (v distinguishedNameValue) Equal(o attr.Value) bool {
+	other, ok := o.(distinguishedNameValue)
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+// Canonical returns the canonical form of the distinguished name.
+func (v distinguishedNameValue) Canonical() (string, error) {
+	return canonicalDistinguishedName(v.ValueString())
+}
+
+func (v distinguishedNameValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(distinguishedNameValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+	dn, err := v.Canonical()
+	if err != nil {
+		return false, diags
+	}
+	newDN, err := newValue.Canonical()
+	if err != nil {
+		return false, diags
+	}
+	return dn == newDN, diags
+}
+
+func (v distinguishedNameValue) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+	if _, err := v.Canonical(); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Distinguished Name", fmt.Sprintf("Expected an RFC 4514 distinguished name, such as \"CN=www.example.com,O=Example,C=US\", got %q: %v", v.ValueString(), err))
+	}
+}
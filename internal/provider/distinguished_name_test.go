@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalDistinguishedName(t *testing.T) {
+	tests := []struct {
+		dn   string
+		want string
+		err  bool
+	}{
+		{dn: "CN=www.example.com,O=Example,C=US", want: "CN=www.example.com,O=Example,C=US"},
+		{dn: "cn = www.example.com , o=Example ,  c=US", want: "CN=www.example.com,O=Example,C=US"},
+		{dn: "2.5.4.3=test,S=WA", want: "CN=test,ST=WA"},
+		{dn: `CN=Example\2C Inc.,O=A\+B`, want: `CN=Example\, Inc.,O=A\+B`},
+		{dn: `CN=\ padded\ ,OU=\#1`, want: `CN=\ padded\ ,OU=\#1`},
+		{dn: "OU=b+CN=a,DC=example,DC=com", want: "CN=a+OU=b,DC=example,DC=com"},
+		{dn: "CN=#0c0474657374", want: "CN=test"},
+		{dn: "1.2.3.4=custom", want: "1.2.3.4=custom"},
+		{dn: "", err: true},
+		{dn: "CN", err: true},
+		{dn: "CN=a,", err: true},
+		{dn: "XX=a", err: true},
+		{dn: `CN=a"b`, err: true},
+		{dn: `CN=a\`, err: true},
+		{dn: `CN=a\zz`, err: true},
+		{dn: "CN=#zz", err: true},
+		{dn: "CN=#020101", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dn, func(t *testing.T) {
+			got, err := canonicalDistinguishedName(tt.dn)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseDistinguishedName(t *testing.T) {
+	rdns, err := parseDistinguishedName("CN=www.example.com,O=Example,C=US")
+	require.NoError(t, err)
+	var name pkix.Name
+	name.FillFromRDNSequence(&rdns)
+	require.Equal(t, "www.example.com", name.CommonName)
+	require.Equal(t, []string{"Example"}, name.Organization)
+	require.Equal(t, []string{"US"}, name.Country)
+	require.Equal(t, "CN=www.example.com,O=Example,C=US", name.String())
+}
+
+func TestDistinguishedNameSemanticEquals(t *testing.T) {
+	v := distinguishedNameValue{StringValue: basetypes.NewStringValue("CN=test,O=Example")}
+
+	equal, diags := v.StringSemanticEquals(context.Background(), distinguishedNameValue{StringValue: basetypes.NewStringValue("cn=test, o=Example")})
+	require.False(t, diags.HasError())
+	require.True(t, equal)
+
+	equal, diags = v.StringSemanticEquals(context.Background(), distinguishedNameValue{StringValue: basetypes.NewStringValue("O=Example,CN=test")})
+	require.False(t, diags.HasError())
+	require.False(t, equal)
+}
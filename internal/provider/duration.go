@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// durationUnitsDescription documents the syntax accepted by parseDuration.
+const durationUnitsDescription = "Durations are Go duration strings, such as `36h`, also accepting the `d` (24 hours), `w` (7 days), `mo` (30 days) and `y` (365 days) units, such as `90d` or `1y`. Durations are compared by length, so that rewriting `8760h` as `1y` plans no change."
+
+// extendedDurationUnits holds the hours of the units parseDuration accepts on
+// top of the time.ParseDuration ones.
+var extendedDurationUnits = map[string]float64{
+	"d":  24,
+	"w":  7 * 24,
+	"mo": 30 * 24,
+	"y":  365 * 24,
+}
+
+var extendedDurationRegexp = regexp.MustCompile(`([0-9]*\.?[0-9]+)(mo|[dwy])`)
+
+// parseDuration parses a duration string like time.ParseDuration, also
+// accepting the days, weeks, months and years units.
+func parseDuration(s string) (time.Duration, error) {
+	var err error
+	hours := extendedDurationRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		sm := extendedDurationRegexp.FindStringSubmatch(m)
+		v, e := strconv.ParseFloat(sm[1], 64)
+		if e != nil {
+			err = e
+			return m
+		}
+		return strconv.FormatFloat(v*extendedDurationUnits[sm[2]], 'f', -1, 64) + "h"
+	})
+	if err != nil {
+		return 0, fmt.Errorf("time: invalid duration %q", s)
+	}
+	d, err := time.ParseDuration(hours)
+	if err != nil {
+		return 0, fmt.Errorf("time: invalid duration %q", s)
+	}
+	return d, nil
+}
+
+var _ validator.String = timestampValidator{}
+
+// timestampValidator validates that a string is an RFC3339 timestamp.
+type timestampValidator struct{}
+
+func (v timestampValidator) Description(ctx context.Context) string {
+	return "value must be an RFC3339 timestamp"
+}
+
+func (v timestampValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v timestampValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Timestamp",
+			fmt.Sprintf("Expected an RFC3339 timestamp, got %q: %v", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
+
+var _ basetypes.StringTypable = durationType{}
+
+// durationType is a string attribute type holding a duration string parsed
+// by parseDuration. Values of the same duration are semantically equal.
+type durationType struct {
+	basetypes.StringType
+}
+
+func (t durationType) String() string {
+	return "durationType"
+}
+
+func (t durationType) Equal(o attr.Type) bool {
+	_, ok := o.(durationType)
+	return ok
+}
+
+func (t durationType) ValueType(ctx context.Context) attr.Value {
+	return durationValue{}
+}
+
+func (t durationType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return durationValue{StringValue: in}, nil
+}
+
+func (t durationType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	v, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", v)
+	}
+	return durationValue{StringValue: s}, nil
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = durationValue{}
+var _ xattr.ValidateableAttribute = durationValue{}
+
+// durationValue is a value of durationType.
+type durationValue struct {
+	basetypes.StringValue
+}
+
+func durationNull() durationValue {
+	return durationValue{StringValue: basetypes.NewStringNull()}
+}
+
+func durationUnknown() durationValue {
+	return durationValue{StringValue: basetypes.NewStringUnknown()}
+}
+
+func durationString(s string) durationValue {
+	return durationValue{StringValue: basetypes.NewStringValue(s)}
+}
+
+func (v durationValue) Type(ctx context.Context) attr.Type {
+	return durationType{}
+}
+
+func (v durationValue) Equal(o attr.Value) bool {
+	other, ok := o.(durationValue)
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+// Duration returns the parsed duration.
+func (v durationValue) Duration() (time.Duration, error) {
+	return parseDuration(v.ValueString())
+}
+
+func (v durationValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(durationValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+	d, err := v.Duration()
+	if err != nil {
+		return false, diags
+	}
+	newD, err := newValue.Duration()
+	if err != nil {
+		return false, diags
+	}
+	return d == newD, diags
+}
+
+func (v durationValue) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+	if _, err := v.Duration(); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Duration String", fmt.Sprintf("Invalid duration string: %v", err))
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     time.Duration
+		invalid  bool
+	}{
+		{duration: "36h", want: 36 * time.Hour},
+		{duration: "90m", want: 90 * time.Minute},
+		{duration: "1ms", want: time.Millisecond},
+		{duration: "90d", want: 90 * 24 * time.Hour},
+		{duration: "1.5d", want: 36 * time.Hour},
+		{duration: "13w", want: 13 * 7 * 24 * time.Hour},
+		{duration: "6mo", want: 6 * 30 * 24 * time.Hour},
+		{duration: "1y", want: 365 * 24 * time.Hour},
+		{duration: "1y2d3h", want: (365*24 + 2*24 + 3) * time.Hour},
+		{duration: "-1d", want: -24 * time.Hour},
+		{duration: "", invalid: true},
+		{duration: "1", invalid: true},
+		{duration: "1x", invalid: true},
+		{duration: "d", invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			got, err := parseDuration(tt.duration)
+			if tt.invalid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDurationSemanticEquals(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		old, new string
+		want     bool
+	}{
+		{old: "8760h", new: "1y", want: true},
+		{old: "24h", new: "1d", want: true},
+		{old: "1w", new: "7d", want: true},
+		{old: "1d", new: "25h", want: false},
+		{old: "invalid", new: "invalid", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.old+"/"+tt.new, func(t *testing.T) {
+			got, diags := durationString(tt.old).StringSemanticEquals(ctx, durationString(tt.new))
+			require.False(t, diags.HasError())
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
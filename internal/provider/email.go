@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/net/idna"
+)
+
+const (
+	maxEmailLocalPartLength = 64
+	maxEmailAddressLength   = 254
+)
+
+// normalizeEmailAddress validates an email address as defined by RFC 5321,
+// extended to internationalized domains by RFC 6531, and returns it with the
+// domain converted to lower case A-labels as required in an rfc822Name
+// subject alternative name.
+func normalizeEmailAddress(s string) (string, error) {
+	at := strings.LastIndexByte(s, '@')
+	if at < 0 {
+		return "", errors.New("missing @ separating the local part and the domain")
+	}
+	local, domain := s[:at], s[at+1:]
+
+	if err := validateEmailLocalPart(local); err != nil {
+		return "", err
+	}
+	if domain == "" {
+		return "", errors.New("empty domain")
+	}
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return "", fmt.Errorf("invalid domain %q: %w", domain, err)
+	}
+	if !strings.Contains(ascii, ".") {
+		return "", fmt.Errorf("domain %q is not fully qualified", domain)
+	}
+
+	address := local + "@" + strings.ToLower(ascii)
+	if len(address) > maxEmailAddressLength {
+		return "", fmt.Errorf("address longer than %d characters", maxEmailAddressLength)
+	}
+	return address, nil
+}
+
+// validateEmailLocalPart validates the local part of an email address as a
+// dot-string or a quoted-string. Non-ASCII local parts are valid under
+// RFC 6531 but cannot be encoded in an rfc822Name, which is ASCII only.
+func validateEmailLocalPart(local string) error {
+	if local == "" {
+		return errors.New("empty local part")
+	}
+	if len(local) > maxEmailLocalPartLength {
+		return fmt.Errorf("local part longer than %d characters", maxEmailLocalPartLength)
+	}
+	for _, r := range local {
+		if r > 0x7f {
+			return errors.New("local part contains non-ASCII characters, which certificates only support as SmtpUTF8Mailbox names that EZCA does not issue")
+		}
+	}
+
+	if len(local) >= 2 && local[0] == '"' && local[len(local)-1] == '"' {
+		quoted := local[1 : len(local)-1]
+		for i := 0; i < len(quoted); i++ {
+			c := quoted[i]
+			if c == '\\' {
+				i++
+				if i == len(quoted) {
+					return errors.New("unterminated escape in quoted local part")
+				}
+				continue
+			}
+			if c == '"' || c < 0x20 || c == 0x7f {
+				return fmt.Errorf("invalid character %q in quoted local part", c)
+			}
+		}
+		return nil
+	}
+
+	for _, atom := range strings.Split(local, ".") {
+		if atom == "" {
+			return errors.New("local part has a leading, trailing or repeated dot")
+		}
+		for i := 0; i < len(atom); i++ {
+			if !isEmailAtext(atom[i]) {
+				return fmt.Errorf("invalid character %q in local part", atom[i])
+			}
+		}
+	}
+	return nil
+}
+
+func isEmailAtext(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		strings.IndexByte("!#$%&'*+-/=?^_`{|}~", c) >= 0
+}
+
+var _ validator.String = emailAddressValidator{}
+
+// emailAddressValidator validates that a string is an email address that
+// can be issued as a subject alternative name.
+type emailAddressValidator struct{}
+
+func (v emailAddressValidator) Description(ctx context.Context) string {
+	return "value must be an email address with an ASCII local part"
+}
+
+func (v emailAddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v emailAddressValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := normalizeEmailAddress(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Email Address",
+			fmt.Sprintf("Invalid email address %q: %v", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEmailAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+		invalid bool
+	}{
+		{address: "user@example.com", want: "user@example.com"},
+		{address: "First.Last+tag@Example.COM", want: "First.Last+tag@example.com"},
+		{address: "user@Bücher.example", want: "user@xn--bcher-kva.example"},
+		{address: `"john doe"@example.com`, want: `"john doe"@example.com`},
+		{address: "user.example.com", invalid: true},
+		{address: "@example.com", invalid: true},
+		{address: "user@", invalid: true},
+		{address: "user@localhost", invalid: true},
+		{address: ".user@example.com", invalid: true},
+		{address: "us..er@example.com", invalid: true},
+		{address: "us er@example.com", invalid: true},
+		{address: "üser@example.com", invalid: true},
+		{address: "user@exa_mple.com", invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			got, err := normalizeEmailAddress(tt.address)
+			if tt.invalid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
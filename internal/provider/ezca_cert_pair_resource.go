@@ -0,0 +1,452 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/markeytos/ezca-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &KeytosEzcaCertPairResource{}
+var _ resource.ResourceWithModifyPlan = &KeytosEzcaCertPairResource{}
+
+func NewKeytosEzcaCertPairResource() resource.Resource {
+	return &KeytosEzcaCertPairResource{}
+}
+
+// KeytosEzcaCertPairResource defines the resource implementation. Each
+// certificate of the pair goes through the KeytosEzcaSslLeafCertResource
+// lifecycle.
+type KeytosEzcaCertPairResource struct {
+	leaf KeytosEzcaSslLeafCertResource
+}
+
+// KeytosEzcaCertPairResourceModel describes the resource data model.
+type KeytosEzcaCertPairResourceModel struct {
+	AuthorityID             types.String           `tfsdk:"authority_id"`
+	ServerTemplateID        types.String           `tfsdk:"server_template_id"`
+	ClientTemplateID        types.String           `tfsdk:"client_template_id"`
+	CertRequestPEM          types.String           `tfsdk:"cert_request_pem"`
+	ValidityPeriod          durationValue          `tfsdk:"validity_period"`
+	OverwriteSubjectNameStr distinguishedNameValue `tfsdk:"overwrite_subject_name_str"`
+	DNSNames                types.List             `tfsdk:"dns_names"`
+	EarlyRenewalPeriod      durationValue          `tfsdk:"early_renewal_period"`
+
+	ServerCertPEM           types.String `tfsdk:"server_cert_pem"`
+	ServerCertThumbprintHex types.String `tfsdk:"server_cert_thumbprint_hex"`
+	ServerCertSerialNumber  types.String `tfsdk:"server_cert_serial_number"`
+	ClientCertPEM           types.String `tfsdk:"client_cert_pem"`
+	ClientCertThumbprintHex types.String `tfsdk:"client_cert_thumbprint_hex"`
+	ClientCertSerialNumber  types.String `tfsdk:"client_cert_serial_number"`
+	CAChainPEM              types.List   `tfsdk:"ca_chain_pem"`
+	ValidityNotAfter        types.String `tfsdk:"validity_not_after"`
+	ReadyForRenewal         types.Bool   `tfsdk:"ready_for_renewal"`
+}
+
+func (r *KeytosEzcaCertPairResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ezca_cert_pair"
+}
+
+func (r *KeytosEzcaCertPairResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	requiresReplace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates a server authentication and a client authentication certificate for the same certificate request, issued by two templates of an EZCA SSL authority, as commonly needed to bootstrap mutual TLS. Both certificates are issued or none is: when the client certificate cannot be issued, the server certificate is revoked. Both certificates are revoked when the resource is deleted. When ready for renewal, both are renewed in place, the previous certificates being revoked only once both new ones are issued.",
+
+		Attributes: map[string]schema.Attribute{
+			"authority_id": schema.StringAttribute{
+				MarkdownDescription: "EZCA SSL authority identifier",
+				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"server_template_id": schema.StringAttribute{
+				MarkdownDescription: "EZCA authority SSL template identifier issuing the server authentication certificate",
+				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"client_template_id": schema.StringAttribute{
+				MarkdownDescription: "EZCA authority SSL template identifier issuing the client authentication certificate",
+				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"cert_request_pem": schema.StringAttribute{
+				MarkdownDescription: "Certificate request data in PEM format",
+				Required:            true,
+				PlanModifiers:       requiresReplace,
+			},
+			"validity_period": schema.StringAttribute{
+				CustomType:          durationType{},
+				MarkdownDescription: "Validity period that the certificates will remain valid for. " + durationUnitsDescription,
+				Required:            true,
+				PlanModifiers:       requiresReplace,
+			},
+			"overwrite_subject_name_str": schema.StringAttribute{
+				CustomType:          distinguishedNameType{},
+				MarkdownDescription: "Set to override the Subject Name of the certificates as an RFC 4514 distinguished name string, compared in canonical form",
+				Optional:            true,
+				PlanModifiers:       requiresReplace,
+			},
+			"dns_names": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "DNS names to add to the subject alternative names of the certificates",
+				Optional:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"early_renewal_period": schema.StringAttribute{
+				CustomType:          durationType{},
+				MarkdownDescription: "Resource will consider the certificates ready for renewal early by the duration defined here. Accepts the same duration units as `validity_period`.",
+				Optional:            true,
+			},
+
+			"server_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "Server authentication certificate data in PEM format.",
+				Computed:            true,
+			},
+			"server_cert_thumbprint_hex": schema.StringAttribute{
+				MarkdownDescription: "Server authentication certificate thumbprint. This is a SHA-1 sum of the raw certificate contents.",
+				Computed:            true,
+			},
+			"server_cert_serial_number": schema.StringAttribute{
+				MarkdownDescription: "Server authentication certificate serial number.",
+				Computed:            true,
+			},
+			"client_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "Client authentication certificate data in PEM format.",
+				Computed:            true,
+			},
+			"client_cert_thumbprint_hex": schema.StringAttribute{
+				MarkdownDescription: "Client authentication certificate thumbprint. This is a SHA-1 sum of the raw certificate contents.",
+				Computed:            true,
+			},
+			"client_cert_serial_number": schema.StringAttribute{
+				MarkdownDescription: "Client authentication certificate serial number.",
+				Computed:            true,
+			},
+			"ca_chain_pem": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Authority chain of the server authentication certificate, as a list of certificates in PEM format from the issuing authority up to the root.",
+				Computed:            true,
+			},
+			"validity_not_after": schema.StringAttribute{
+				MarkdownDescription: "Time prior which both certificates are valid as an RFC3339 timestamp. Earliest expiration time stamp of the pair.",
+				Computed:            true,
+			},
+			"ready_for_renewal": schema.BoolAttribute{
+				MarkdownDescription: "True when a certificate is expired or when in the early renewal period. When true, the next plan renews the pair.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *KeytosEzcaCertPairResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.leaf.Configure(ctx, req, resp)
+}
+
+func (r *KeytosEzcaCertPairResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data KeytosEzcaCertPairResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tag := sourceTag(ctx, req.ProviderMeta, &resp.Diagnostics)
+
+	server, client := r.issue(ctx, &data, tag, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.save(server, client)
+
+	tflog.Trace(ctx, "created a resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeytosEzcaCertPairResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data KeytosEzcaCertPairResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The pair is renewed as a whole, when the earliest expiring certificate
+	// is ready for renewal
+	server := data.leafModel(data.ServerTemplateID, ezca.ExtKeyUsageServerAuth)
+	server.EarlyRenewalPeriod = data.EarlyRenewalPeriod
+	r.leaf.read(ctx, &server, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ReadyForRenewal = server.ReadyForRenewal
+
+	tflog.Trace(ctx, "read and updated the resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeytosEzcaCertPairResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var newm, oldm KeytosEzcaCertPairResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newm)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &oldm)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The pair is renewed when planned by ModifyPlan
+	if newm.ServerCertPEM.IsUnknown() {
+		r.renew(ctx, &newm, &oldm, sourceTag(ctx, req.ProviderMeta, &resp.Diagnostics), &resp.Diagnostics)
+		// The previous pair is kept when the new one could not be issued
+		if newm.ServerCertPEM.IsUnknown() {
+			return
+		}
+		tflog.Trace(ctx, "renewed the resource")
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &newm)...)
+		return
+	}
+
+	// Every other argument requires replacement, only the early renewal
+	// period can change
+	erp, err := parseEarlyRenewalPeriod(newm.EarlyRenewalPeriod)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("early_renewal_period"), "Invalid Early Renewal Period", fmt.Sprintf("Invalid duration string: %v", err))
+		return
+	}
+	notAfter, err := time.Parse(time.RFC3339, oldm.ValidityNotAfter.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Internal State", fmt.Sprintf("Invalid certificate expiration time stamp: %q: %v", oldm.ValidityNotAfter.ValueString(), err))
+		return
+	}
+	newm.ServerCertPEM = oldm.ServerCertPEM
+	newm.ServerCertThumbprintHex = oldm.ServerCertThumbprintHex
+	newm.ServerCertSerialNumber = oldm.ServerCertSerialNumber
+	newm.ClientCertPEM = oldm.ClientCertPEM
+	newm.ClientCertThumbprintHex = oldm.ClientCertThumbprintHex
+	newm.ClientCertSerialNumber = oldm.ClientCertSerialNumber
+	newm.CAChainPEM = oldm.CAChainPEM
+	newm.ValidityNotAfter = oldm.ValidityNotAfter
+	newm.ReadyForRenewal = types.BoolValue(readyForRenewal(notAfter, erp))
+
+	tflog.Trace(ctx, "updated the resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &newm)...)
+}
+
+func (r *KeytosEzcaCertPairResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.leaf.fipsMode && !req.Plan.Raw.IsNull() {
+		var csrPEM types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("cert_request_pem"), &csrPEM)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Unknown requests are checked again during apply
+		if !csrPEM.IsUnknown() {
+			checkFIPSCertificateRequest(csrPEM.ValueString(), &resp.Diagnostics)
+		}
+	}
+
+	// Nothing to renew on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var notAfterStr types.String
+	var erpStr durationValue
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("validity_not_after"), &notAfterStr)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("early_renewal_period"), &erpStr)...)
+	if resp.Diagnostics.HasError() || erpStr.IsUnknown() {
+		return
+	}
+	notAfter, err := time.Parse(time.RFC3339, notAfterStr.ValueString())
+	if err != nil {
+		return
+	}
+	erp, err := parseEarlyRenewalPeriod(erpStr)
+	if err != nil || !readyForRenewal(notAfter, erp) {
+		return
+	}
+
+	// Renewed in place by Update, so that the previous pair is only revoked
+	// once the new one is issued
+	for _, name := range []string{
+		"server_cert_pem", "server_cert_thumbprint_hex", "server_cert_serial_number",
+		"client_cert_pem", "client_cert_thumbprint_hex", "client_cert_serial_number",
+		"validity_not_after",
+	} {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), types.StringUnknown())...)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ca_chain_pem"), types.ListUnknown(types.StringType))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ready_for_renewal"), types.BoolUnknown())...)
+}
+
+func (r *KeytosEzcaCertPairResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data KeytosEzcaCertPairResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.revoke(ctx, &data, &resp.Diagnostics)
+}
+
+// issue issues the server and client certificates of the model. Both
+// certificates are issued or none is: when the client certificate cannot be
+// issued, the server certificate is revoked.
+func (r *KeytosEzcaCertPairResource) issue(ctx context.Context, m *KeytosEzcaCertPairResourceModel, tag string, diags *diag.Diagnostics) (server, client *KeytosEzcaSslLeafCertResourceModel) {
+	s := m.leafModel(m.ServerTemplateID, ezca.ExtKeyUsageServerAuth)
+	s.sourceTag = tag
+	r.leaf.create(ctx, &s, diags)
+	if diags.HasError() {
+		return nil, nil
+	}
+	tflog.Trace(ctx, "issued server certificate")
+
+	c := m.leafModel(m.ClientTemplateID, ezca.ExtKeyUsageClientAuth)
+	c.sourceTag = tag
+	r.leaf.create(ctx, &c, diags)
+	if diags.HasError() {
+		var revokeDiags diag.Diagnostics
+		r.leaf.delete(ctx, &s, &revokeDiags)
+		if revokeDiags.HasError() {
+			diags.AddError(
+				"Error Revoking Server Certificate",
+				fmt.Sprintf("The client certificate could not be issued and the server certificate with serial number %s could not be revoked, revoke it manually: %s", s.CertSerialNumber.ValueString(), diagnosticsDetail(revokeDiags)),
+			)
+		}
+		return nil, nil
+	}
+	tflog.Trace(ctx, "issued client certificate")
+	return &s, &c
+}
+
+// renew issues a new pair into newm, then revokes the pair of oldm. The
+// certificates of newm are left unknown when the new pair could not be
+// issued, the previous pair then being kept valid.
+func (r *KeytosEzcaCertPairResource) renew(ctx context.Context, newm, oldm *KeytosEzcaCertPairResourceModel, tag string, diags *diag.Diagnostics) {
+	server, client := r.issue(ctx, newm, tag, diags)
+	if diags.HasError() {
+		return
+	}
+	newm.save(server, client)
+
+	// The new pair is saved even when the previous one cannot be revoked
+	var revokeDiags diag.Diagnostics
+	r.revoke(ctx, oldm, &revokeDiags)
+	if revokeDiags.HasError() {
+		diags.AddError(
+			"Error Revoking Certificate",
+			fmt.Sprintf("The new certificates were issued, but an error was encountered when trying to revoke the previous certificates with serial numbers %s and %s: %s", oldm.ServerCertSerialNumber.ValueString(), oldm.ClientCertSerialNumber.ValueString(), diagnosticsDetail(revokeDiags)),
+		)
+	}
+}
+
+// revoke revokes both certificates of the model, even if one of them fails.
+func (r *KeytosEzcaCertPairResource) revoke(ctx context.Context, m *KeytosEzcaCertPairResourceModel, diags *diag.Diagnostics) {
+	server := m.leafModel(m.ServerTemplateID, ezca.ExtKeyUsageServerAuth)
+	server.CertPEM = m.ServerCertPEM
+	server.CertThumbprintHex = m.ServerCertThumbprintHex
+	r.leaf.delete(ctx, &server, diags)
+
+	client := m.leafModel(m.ClientTemplateID, ezca.ExtKeyUsageClientAuth)
+	client.CertPEM = m.ClientCertPEM
+	client.CertThumbprintHex = m.ClientCertThumbprintHex
+	r.leaf.delete(ctx, &client, diags)
+}
+
+// leafModel returns the model of the certificate of the pair issued by the
+// template with the extended key usage. Attributes the pair does not
+// configure are unknown so that the leaf certificate defaults apply.
+func (m *KeytosEzcaCertPairResourceModel) leafModel(templateID types.String, eku ezca.ExtKeyUsage) KeytosEzcaSslLeafCertResourceModel {
+	l := KeytosEzcaSslLeafCertResourceModel{
+		AuthorityID:                       m.AuthorityID,
+		TemplateID:                        templateID,
+		CertRequestPEM:                    m.CertRequestPEM,
+		ValidityPeriod:                    m.ValidityPeriod,
+		KeyUsages:                         types.SetUnknown(types.StringType),
+		ExtendedKeyUsages:                 types.SetValueMust(types.StringType, []attr.Value{types.StringValue(string(eku))}),
+		ForwardRequestUsages:              types.BoolValue(false),
+		OverwriteSubjectName:              types.ObjectUnknown(subjectNameAttributeTypes),
+		OverwriteSubjectNameStr:           m.OverwriteSubjectNameStr,
+		AdditionalSubjectAlternativeNames: types.ObjectUnknown(subjectAlternativeNamesAttributeTypes),
+		EarlyRenewalPeriod:                m.EarlyRenewalPeriod,
+		PEMLineLength:                     types.Int64Value(defaultPEMLineLength),
+		PEMExplanatoryText:                types.BoolValue(false),
+		ValidityNotAfter:                  m.ValidityNotAfter,
+	}
+	if l.OverwriteSubjectNameStr.IsNull() {
+		l.OverwriteSubjectNameStr = distinguishedNameUnknown()
+	}
+	if l.EarlyRenewalPeriod.IsNull() {
+		l.EarlyRenewalPeriod = durationUnknown()
+	}
+	if !m.DNSNames.IsNull() {
+		l.AdditionalSubjectAlternativeNames = types.ObjectValueMust(subjectAlternativeNamesAttributeTypes, map[string]attr.Value{
+			"dns_names":       m.DNSNames,
+			"email_addresses": types.ListNull(types.StringType),
+			"ip_addresses":    types.ListNull(types.StringType),
+			"uris":            types.ListNull(types.StringType),
+		})
+	}
+	return l
+}
+
+// save saves the issued server and client certificates into the model.
+func (m *KeytosEzcaCertPairResourceModel) save(server, client *KeytosEzcaSslLeafCertResourceModel) {
+	m.ServerCertPEM = server.CertPEM
+	m.ServerCertThumbprintHex = server.CertThumbprintHex
+	m.ServerCertSerialNumber = server.CertSerialNumber
+	m.ClientCertPEM = client.CertPEM
+	m.ClientCertThumbprintHex = client.CertThumbprintHex
+	m.ClientCertSerialNumber = client.CertSerialNumber
+	m.CAChainPEM = server.CAChainPEM
+	m.ValidityNotAfter = server.ValidityNotAfter
+	if client.ValidityNotAfter.ValueString() < server.ValidityNotAfter.ValueString() {
+		m.ValidityNotAfter = client.ValidityNotAfter
+	}
+	m.ReadyForRenewal = types.BoolValue(server.ReadyForRenewal.ValueBool() || client.ReadyForRenewal.ValueBool())
+}
+
+// diagnosticsDetail joins the summaries and details of the diagnostics.
+func diagnosticsDetail(diags diag.Diagnostics) string {
+	var details []string
+	for _, d := range diags {
+		details = append(details, d.Summary()+": "+d.Detail())
+	}
+	return strings.Join(details, "; ")
+}
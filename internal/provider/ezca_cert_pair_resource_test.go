@@ -0,0 +1,255 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/compare"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/markeytos/ezca-go"
+	"github.com/markeytos/terraform-provider-keytos/internal/acctest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccKeytosEzcaCertPair(t *testing.T) {
+	certPEMRegexp, err := regexp.Compile(`-----BEGIN CERTIFICATE-----[\r\n]+([A-Za-z0-9+/=\r\n]+)[\r\n]+-----END CERTIFICATE-----`)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccKeytosEzcaCertPairConfig("24h", "0"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_cert_pair.test",
+						tfjsonpath.New("server_cert_pem"),
+						knownvalue.StringRegexp(certPEMRegexp),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_cert_pair.test",
+						tfjsonpath.New("client_cert_pem"),
+						knownvalue.StringRegexp(certPEMRegexp),
+					),
+					statecheck.CompareValuePairs(
+						"keytos_ezca_cert_pair.test",
+						tfjsonpath.New("server_cert_serial_number"),
+						"keytos_ezca_cert_pair.test",
+						tfjsonpath.New("client_cert_serial_number"),
+						compare.ValuesDiffer(),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_cert_pair.test",
+						tfjsonpath.New("ready_for_renewal"),
+						knownvalue.Bool(false),
+					),
+				},
+			},
+			// Update early renewal period in place, the pair is then ready
+			// for renewal and the next plan renews it in place
+			{
+				Config:             testAccKeytosEzcaCertPairConfig("24h", "48h"),
+				ExpectNonEmptyPlan: true,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_cert_pair.test",
+						tfjsonpath.New("ready_for_renewal"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestCertPairLeafModel(t *testing.T) {
+	dnsNames := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("test.com")})
+	m := KeytosEzcaCertPairResourceModel{
+		AuthorityID:             types.StringValue(test_authority_id),
+		ServerTemplateID:        types.StringValue(test_template_id),
+		ClientTemplateID:        types.StringValue(test_template_id),
+		CertRequestPEM:          types.StringValue(testCSR),
+		ValidityPeriod:          durationString("24h"),
+		OverwriteSubjectNameStr: distinguishedNameNull(),
+		DNSNames:                dnsNames,
+		EarlyRenewalPeriod:      durationNull(),
+	}
+
+	for _, eku := range []ezca.ExtKeyUsage{ezca.ExtKeyUsageServerAuth, ezca.ExtKeyUsageClientAuth} {
+		t.Run(string(eku), func(t *testing.T) {
+			l := m.leafModel(m.ServerTemplateID, eku)
+			var diags diag.Diagnostics
+			opts := buildSignOptions(context.Background(), &l, &diags)
+			require.False(t, diags.HasError(), "%v", diags)
+			require.Equal(t, []ezca.ExtKeyUsage{eku}, opts.ExtendedKeyUsages)
+			require.Equal(t, []string{"test.com"}, opts.DNSNames)
+			require.Empty(t, opts.SubjectName)
+		})
+	}
+}
+
+func testAccKeytosEzcaCertPairConfig(validity, earlyRenewal string) string {
+	return fmt.Sprintf(`
+resource "keytos_ezca_cert_pair" "test" {
+  authority_id = %q
+  server_template_id = %q
+  client_template_id = %q
+  cert_request_pem = %q
+  validity_period = %q
+  dns_names = ["test.com"]
+  early_renewal_period = %q
+}
+`, test_authority_id, test_template_id, test_template_id, testCSR, validity, earlyRenewal)
+}
+
+func TestAccKeytosEzcaCertPair_fipsMode(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKeytosEzcaCertPairFIPSConfig(testCertificateRequestPEM(t, key)),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Non-FIPS Certificate Request`),
+			},
+		},
+	})
+}
+
+func testAccKeytosEzcaCertPairFIPSConfig(csrPEM string) string {
+	return fmt.Sprintf(`
+provider "keytos" {
+  fips_mode = true
+}
+
+resource "keytos_ezca_cert_pair" "test" {
+  authority_id = %q
+  server_template_id = %q
+  client_template_id = %q
+  cert_request_pem = %q
+  validity_period = "24h"
+  dns_names = ["test.com"]
+}
+`, test_authority_id, test_template_id, test_template_id, csrPEM)
+}
+
+func TestCertPairRead(t *testing.T) {
+	ctx := context.Background()
+	// EZCA cannot be reached, so the refresh keeps the state after
+	// flagging the pair for renewal
+	c, err := newEzcaClient([]string{"https://127.0.0.1:1"}, testCredential{}, retryPolicy{maxAttempts: 1})
+	require.NoError(t, err)
+	r := &KeytosEzcaCertPairResource{leaf: KeytosEzcaSslLeafCertResource{client: c, refreshFailureMode: refreshFailureModeWarnAndKeepState}}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError(), "%v", schemaResp.Diagnostics)
+
+	m := testCertPairModel(time.Hour)
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	diags := state.Set(ctx, &m)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	var got KeytosEzcaCertPairResourceModel
+	diags = resp.State.Get(ctx, &got)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.True(t, got.ReadyForRenewal.ValueBool())
+}
+
+func TestCertPairRenewal(t *testing.T) {
+	ctx := context.Background()
+	// EZCA cannot be reached, so signing the new pair fails
+	c, err := newEzcaClient([]string{"https://127.0.0.1:1"}, testCredential{}, retryPolicy{maxAttempts: 1})
+	require.NoError(t, err)
+	r := &KeytosEzcaCertPairResource{leaf: KeytosEzcaSslLeafCertResource{client: c}}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError(), "%v", schemaResp.Diagnostics)
+
+	m := testCertPairModel(time.Hour)
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	diags := state.Set(ctx, &m)
+	require.False(t, diags.HasError(), "%v", diags)
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: state.Raw.Copy()}
+
+	// The pair is renewed in place rather than replaced, so that it is not
+	// revoked before the new one is issued
+	planResp := fwresource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{State: state, Plan: plan}, &planResp)
+	require.False(t, planResp.Diagnostics.HasError(), "%v", planResp.Diagnostics)
+	require.Empty(t, planResp.RequiresReplace)
+	var planned KeytosEzcaCertPairResourceModel
+	diags = planResp.Plan.Get(ctx, &planned)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.True(t, planned.ServerCertPEM.IsUnknown())
+	require.True(t, planned.ClientCertPEM.IsUnknown())
+	require.True(t, planned.ReadyForRenewal.IsUnknown())
+
+	// The previous pair is kept when the new one cannot be issued
+	updateResp := fwresource.UpdateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}}
+	r.Update(ctx, fwresource.UpdateRequest{State: state, Plan: planResp.Plan}, &updateResp)
+	require.True(t, updateResp.Diagnostics.HasError())
+	require.True(t, updateResp.State.Raw.IsNull())
+}
+
+// testCertPairModel returns the model of a pair expiring in expiresIn, which
+// is ready for renewal when less than 2 hours.
+func testCertPairModel(expiresIn time.Duration) KeytosEzcaCertPairResourceModel {
+	return KeytosEzcaCertPairResourceModel{
+		AuthorityID:             types.StringValue(test_authority_id),
+		ServerTemplateID:        types.StringValue(test_template_id),
+		ClientTemplateID:        types.StringValue(test_template_id),
+		CertRequestPEM:          types.StringValue(testCSR),
+		ValidityPeriod:          durationString("24h"),
+		OverwriteSubjectNameStr: distinguishedNameNull(),
+		DNSNames:                types.ListNull(types.StringType),
+		EarlyRenewalPeriod:      durationString("2h"),
+		ServerCertPEM:           types.StringValue("server"),
+		ServerCertThumbprintHex: types.StringValue("server"),
+		ServerCertSerialNumber:  types.StringValue("1"),
+		ClientCertPEM:           types.StringValue("client"),
+		ClientCertThumbprintHex: types.StringValue("client"),
+		ClientCertSerialNumber:  types.StringValue("2"),
+		CAChainPEM:              types.ListValueMust(types.StringType, []attr.Value{}),
+		ValidityNotAfter:        types.StringValue(time.Now().Add(expiresIn).Format(time.RFC3339)),
+		ReadyForRenewal:         types.BoolValue(false),
+	}
+}
+
+// testCredential returns a static token, for clients whose requests are
+// not expected to reach EZCA.
+type testCredential struct{}
+
+func (testCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "test", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
@@ -0,0 +1,304 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/markeytos/ezca-go"
+)
+
+// ezcaClient is the EZCA client shared by data sources and resources. It
+// holds a client per configured EZCA endpoint, primary first, and fails over
+// to the next endpoint when one cannot be reached. When none can, requests
+// are retried according to its retry policy.
+type ezcaClient struct {
+	urls      []string
+	endpoints []*ezca.Client
+	policy    retryPolicy
+}
+
+func newEzcaClient(urls []string, cred azcore.TokenCredential, policy retryPolicy) (*ezcaClient, error) {
+	c := &ezcaClient{
+		urls:      urls,
+		endpoints: make([]*ezca.Client, 0, len(urls)),
+		policy:    policy,
+	}
+	for _, u := range urls {
+		e, err := ezca.NewClient(u, cred)
+		if err != nil {
+			return nil, err
+		}
+		c.endpoints = append(c.endpoints, e)
+	}
+	return c, nil
+}
+
+// clone returns a client for the same endpoints and retry policy with EZCA
+// clients of its own, as these are not safe for concurrent use.
+func (c *ezcaClient) clone(cred azcore.TokenCredential) (*ezcaClient, error) {
+	return newEzcaClient(c.urls, cred, c.policy)
+}
+
+// withPolicy returns a client for the same endpoints using another retry
+// policy.
+func (c *ezcaClient) withPolicy(policy retryPolicy) *ezcaClient {
+	cc := *c
+	cc.policy = policy
+	return &cc
+}
+
+// do calls f through failover, retrying with exponential backoff while it
+// fails with a transient error and attempts remain.
+func (c *ezcaClient) do(ctx context.Context, f func(ctx context.Context, e *ezca.Client) error) error {
+	return c.retry(ctx, isEndpointUnavailable, isTransient, f)
+}
+
+// doOnce calls f like do for requests that must not be repeated once sent,
+// such as signing requests, as EZCA may have processed a request whose
+// response was lost. It only fails over and retries when the request could
+// not be sent.
+func (c *ezcaClient) doOnce(ctx context.Context, f func(ctx context.Context, e *ezca.Client) error) error {
+	return c.retry(ctx, isRequestNotSent, isRequestNotSent, f)
+}
+
+func (c *ezcaClient) retry(ctx context.Context, unavailable, transient func(error) bool, f func(ctx context.Context, e *ezca.Client) error) error {
+	for attempt := 1; ; attempt++ {
+		err := c.failover(ctx, unavailable, f)
+		if err == nil || !transient(err) || attempt >= c.policy.maxAttempts || ctx.Err() != nil {
+			return err
+		}
+		wait := c.policy.wait(attempt)
+		tflog.Warn(ctx, "EZCA request failed with a transient error, retrying", map[string]any{
+			"attempt":      attempt,
+			"max_attempts": c.policy.maxAttempts,
+			"wait":         wait.String(),
+			"error":        err.Error(),
+		})
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// failover calls f with the client of each endpoint in order until f
+// succeeds or fails with an error for which unavailable does not report the
+// endpoint as unavailable. Each call is limited by the policy timeout.
+func (c *ezcaClient) failover(ctx context.Context, unavailable func(error) bool, f func(ctx context.Context, e *ezca.Client) error) error {
+	var err error
+	for i, e := range c.endpoints {
+		err = c.call(ctx, e, f)
+		if err == nil || !unavailable(err) {
+			return err
+		}
+		if i+1 < len(c.endpoints) {
+			tflog.Warn(ctx, "EZCA endpoint unavailable, failing over to the next endpoint", map[string]any{
+				"url":   c.urls[i],
+				"next":  c.urls[i+1],
+				"error": err.Error(),
+			})
+		}
+	}
+	return err
+}
+
+func (c *ezcaClient) call(ctx context.Context, e *ezca.Client, f func(ctx context.Context, e *ezca.Client) error) error {
+	if c.policy.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.policy.timeout)
+		defer cancel()
+	}
+	return f(ctx, e)
+}
+
+func (c *ezcaClient) ListAuthorities(ctx context.Context) (as []*ezca.Authority, err error) {
+	err = c.do(ctx, func(ctx context.Context, e *ezca.Client) error {
+		as, err = e.ListAuthorities(ctx)
+		return err
+	})
+	return
+}
+
+func (c *ezcaClient) ListSSLAuthorities(ctx context.Context) (as []*ezca.SSLAuthority, err error) {
+	err = c.do(ctx, func(ctx context.Context, e *ezca.Client) error {
+		as, err = e.ListSSLAuthorities(ctx)
+		return err
+	})
+	return
+}
+
+// SSLAuthority returns a client for the SSL authority template, validating
+// that the authority template exists.
+func (c *ezcaClient) SSLAuthority(ctx context.Context, authorityID, templateID uuid.UUID) (*sslAuthorityClient, error) {
+	sc := &sslAuthorityClient{
+		client:      c,
+		authorityID: authorityID,
+		templateID:  templateID,
+		authorities: make(map[*ezca.Client]*ezca.SSLAuthorityClient, len(c.endpoints)),
+	}
+	_, err := sc.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// sslAuthorityClient mirrors ezca.SSLAuthorityClient with endpoint failover.
+type sslAuthorityClient struct {
+	client      *ezcaClient
+	authorityID uuid.UUID
+	templateID  uuid.UUID
+	authorities map[*ezca.Client]*ezca.SSLAuthorityClient
+}
+
+func (c *sslAuthorityClient) do(ctx context.Context, f func(ctx context.Context, a *ezca.SSLAuthorityClient) error) error {
+	return c.client.do(ctx, func(ctx context.Context, e *ezca.Client) error {
+		a, err := c.authority(ctx, e)
+		if err != nil {
+			return err
+		}
+		return f(ctx, a)
+	})
+}
+
+func (c *sslAuthorityClient) doOnce(ctx context.Context, f func(ctx context.Context, a *ezca.SSLAuthorityClient) error) error {
+	return c.client.doOnce(ctx, func(ctx context.Context, e *ezca.Client) error {
+		a, err := c.authority(ctx, e)
+		if err != nil {
+			return err
+		}
+		return f(ctx, a)
+	})
+}
+
+// authority returns the authority client of the endpoint, creating it on
+// first use.
+func (c *sslAuthorityClient) authority(ctx context.Context, e *ezca.Client) (*ezca.SSLAuthorityClient, error) {
+	if a, ok := c.authorities[e]; ok {
+		return a, nil
+	}
+	a, err := ezca.NewSSLAuthorityClient(ctx, e, c.authorityID, c.templateID)
+	if err != nil {
+		return nil, err
+	}
+	c.authorities[e] = a
+	return a, nil
+}
+
+func (c *sslAuthorityClient) Info(ctx context.Context) (info *ezca.SSLAuthorityInfo, err error) {
+	err = c.do(ctx, func(ctx context.Context, a *ezca.SSLAuthorityClient) error {
+		info, err = a.Info(ctx)
+		return err
+	})
+	return
+}
+
+// Sign signs the certificate request. It is not retried once sent, so that
+// a lost response does not issue a second certificate.
+func (c *sslAuthorityClient) Sign(ctx context.Context, csr []byte, opts *ezca.SignOptions) (certs []*x509.Certificate, err error) {
+	err = c.doOnce(ctx, func(ctx context.Context, a *ezca.SSLAuthorityClient) error {
+		certs, err = a.Sign(ctx, csr, opts)
+		return err
+	})
+	return
+}
+
+func (c *sslAuthorityClient) RevokeWithThumbprint(ctx context.Context, thumbprint [20]byte) error {
+	return c.do(ctx, func(ctx context.Context, a *ezca.SSLAuthorityClient) error {
+		return a.RevokeWithThumbprint(ctx, thumbprint)
+	})
+}
+
+// isEndpointUnavailable reports whether err was caused by the EZCA endpoint
+// not being reachable, or answering with something other than an EZCA API
+// response such as a gateway error page.
+func isEndpointUnavailable(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	return strings.Contains(err.Error(), "invalid response from server")
+}
+
+// isRequestNotSent reports whether err was raised before the request was
+// sent, when resolving or connecting to the EZCA endpoint, so that sending it
+// again cannot repeat its effects.
+func isRequestNotSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isTransient reports whether err may not happen again when retrying the
+// request: the endpoints being unavailable, the request timing out, or the
+// connection dropping while reading the response. Errors returned by EZCA,
+// such as permission or validation errors, are not transient.
+func isTransient(err error) bool {
+	return isEndpointUnavailable(err) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		strings.Contains(err.Error(), "could not get data")
+}
+
+// ezcaErrorHints are the actions to take on common EZCA API errors, matched
+// on keywords of their lower-cased message.
+var ezcaErrorHints = []struct {
+	keywords [][]string
+	hint     string
+}{
+	{
+		keywords: [][]string{{"domain", "not registered"}, {"domain", "not allowed"}, {"domain", "not found"}},
+		hint:     "A domain of the certificate is not registered with the authority. Register it in the EZCA portal, or remove it from the subject and subject alternative names.",
+	},
+	{
+		// Rate limiting also reports exceeded limits and "too many"
+		// requests, which raising the quota does not solve
+		keywords: [][]string{{"quota"}, {"certificate", "limit exceeded"}},
+		hint:     "The certificate quota of the EZCA subscription is exceeded. Revoke unused certificates or increase the quota of the subscription.",
+	},
+	{
+		// Templates also reject usages as not allowed, so that phrase only
+		// matches when it refers to the identity
+		keywords: [][]string{{"not authorized"}, {"unauthorized"}, {"forbidden"}, {"permission"}, {"access denied"}, {"user", "not allowed"}, {"identity", "not allowed"}},
+		hint:     "The current identity is not permitted to use the authority template. Ask an administrator of the authority to grant it access, and check the permissions of the identity with the keytos_ezca_permission_check data source.",
+	},
+}
+
+// ezcaErrorDetail returns the detail of a diagnostic for an error returned
+// by EZCA, followed by the action to take when it is a common failure.
+func ezcaErrorDetail(err error) string {
+	msg := err.Error()
+	lower := strings.ToLower(msg)
+	for _, h := range ezcaErrorHints {
+		for _, keywords := range h.keywords {
+			if containsAll(lower, keywords) {
+				return msg + "\n\n" + h.hint
+			}
+		}
+	}
+	return msg
+}
+
+func containsAll(s string, substrs []string) bool {
+	for _, substr := range substrs {
+		if !strings.Contains(s, substr) {
+			return false
+		}
+	}
+	return true
+}
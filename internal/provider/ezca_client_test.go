@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/markeytos/ezca-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEzcaClientFailover(t *testing.T) {
+	unreachable := &url.Error{Op: "Post", URL: "https://primary.ezca.io", Err: errors.New("connection refused")}
+	denied := errors.New("api error: not authorized")
+
+	tests := []struct {
+		name    string
+		results []error
+		calls   int
+		err     error
+	}{
+		{name: "primary succeeds", results: []error{nil, nil, nil}, calls: 1},
+		{name: "fails over once", results: []error{unreachable, nil, nil}, calls: 2},
+		{name: "fails over to last", results: []error{unreachable, unreachable, nil}, calls: 3},
+		{name: "all unreachable", results: []error{unreachable, unreachable, unreachable}, calls: 3, err: unreachable},
+		{name: "api errors do not fail over", results: []error{denied, nil, nil}, calls: 1, err: denied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ezcaClient{
+				urls:      []string{"primary", "secondary", "tertiary"},
+				endpoints: []*ezca.Client{{}, {}, {}},
+			}
+			calls := 0
+			err := c.do(context.Background(), func(ctx context.Context, e *ezca.Client) error {
+				require.Same(t, c.endpoints[calls], e)
+				calls++
+				return tt.results[calls-1]
+			})
+			require.Equal(t, tt.calls, calls)
+			require.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestEzcaClientFailoverOnce(t *testing.T) {
+	refused := &url.Error{Op: "Post", URL: "https://primary.ezca.io", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	timeout := &url.Error{Op: "Post", URL: "https://primary.ezca.io", Err: context.DeadlineExceeded}
+	gateway := errors.New("invalid response from server: invalid character '<' looking for beginning of value")
+
+	tests := []struct {
+		name    string
+		results []error
+		calls   int
+		err     error
+	}{
+		{name: "refused connections fail over", results: []error{refused, nil}, calls: 2},
+		{name: "timeouts after sending do not fail over", results: []error{timeout, nil}, calls: 1, err: timeout},
+		{name: "invalid responses do not fail over", results: []error{gateway, nil}, calls: 1, err: gateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ezcaClient{
+				urls:      []string{"primary", "secondary"},
+				endpoints: []*ezca.Client{{}, {}},
+				policy:    retryPolicy{maxAttempts: 1},
+			}
+			calls := 0
+			err := c.doOnce(context.Background(), func(ctx context.Context, e *ezca.Client) error {
+				require.Same(t, c.endpoints[calls], e)
+				calls++
+				return tt.results[calls-1]
+			})
+			require.Equal(t, tt.calls, calls)
+			require.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestEzcaClientRetry(t *testing.T) {
+	unreachable := &url.Error{Op: "Post", URL: "https://primary.ezca.io", Err: errors.New("connection refused")}
+	denied := errors.New("api error: not authorized")
+	dropped := errors.New("could not get data: unexpected EOF")
+
+	tests := []struct {
+		name    string
+		results []error
+		calls   int
+		err     error
+	}{
+		{name: "first attempt succeeds", results: []error{nil, nil, nil}, calls: 1},
+		{name: "retries once", results: []error{unreachable, nil, nil}, calls: 2},
+		{name: "attempts exhausted", results: []error{unreachable, unreachable, unreachable}, calls: 3, err: unreachable},
+		{name: "api errors are not retried", results: []error{denied, nil, nil}, calls: 1, err: denied},
+		{name: "timeouts are retried", results: []error{context.DeadlineExceeded, nil, nil}, calls: 2},
+		{name: "dropped responses are retried", results: []error{dropped, nil, nil}, calls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ezcaClient{
+				urls:      []string{"primary"},
+				endpoints: []*ezca.Client{{}},
+				policy:    retryPolicy{maxAttempts: 3, timeout: time.Minute},
+			}
+			calls := 0
+			err := c.do(context.Background(), func(ctx context.Context, e *ezca.Client) error {
+				_, ok := ctx.Deadline()
+				require.True(t, ok)
+				calls++
+				return tt.results[calls-1]
+			})
+			require.Equal(t, tt.calls, calls)
+			require.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestEzcaClientRetryOnce(t *testing.T) {
+	refused := &url.Error{Op: "Post", URL: "https://primary.ezca.io", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	unresolved := &url.Error{Op: "Post", URL: "https://primary.ezca.io", Err: &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "primary.ezca.io"}}}
+	readTimeout := &url.Error{Op: "Post", URL: "https://primary.ezca.io", Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("i/o timeout")}}
+	dropped := errors.New("could not get data: unexpected EOF")
+	gateway := errors.New("invalid response from server: invalid character '<' looking for beginning of value")
+
+	tests := []struct {
+		name    string
+		results []error
+		calls   int
+		err     error
+	}{
+		{name: "refused connections are retried", results: []error{refused, nil, nil}, calls: 2},
+		{name: "unresolved hosts are retried", results: []error{unresolved, nil, nil}, calls: 2},
+		{name: "timeouts are not retried", results: []error{context.DeadlineExceeded, nil, nil}, calls: 1, err: context.DeadlineExceeded},
+		{name: "read timeouts are not retried", results: []error{readTimeout, nil, nil}, calls: 1, err: readTimeout},
+		{name: "dropped responses are not retried", results: []error{dropped, nil, nil}, calls: 1, err: dropped},
+		{name: "invalid responses are not retried", results: []error{gateway, nil, nil}, calls: 1, err: gateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ezcaClient{
+				urls:      []string{"primary"},
+				endpoints: []*ezca.Client{{}},
+				policy:    retryPolicy{maxAttempts: 3},
+			}
+			calls := 0
+			err := c.doOnce(context.Background(), func(ctx context.Context, e *ezca.Client) error {
+				calls++
+				return tt.results[calls-1]
+			})
+			require.Equal(t, tt.calls, calls)
+			require.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	p := retryPolicy{interval: time.Second, multiplier: 2, maxInterval: 5 * time.Second}
+	require.Equal(t, time.Second, p.wait(1))
+	require.Equal(t, 2*time.Second, p.wait(2))
+	require.Equal(t, 4*time.Second, p.wait(3))
+	require.Equal(t, 5*time.Second, p.wait(4))
+
+	p = retryPolicy{interval: time.Second, multiplier: 1}
+	require.Equal(t, time.Second, p.wait(10))
+}
+
+func TestEzcaErrorDetail(t *testing.T) {
+	tests := []struct {
+		err  string
+		hint string
+	}{
+		{"api error: Domain example.com is not registered for this CA", "A domain of the certificate is not registered"},
+		{"api error: Certificate quota exceeded for subscription", "The certificate quota of the EZCA subscription is exceeded"},
+		{"api error: User is not authorized to request certificates from this template", "The current identity is not permitted"},
+		{"api error: Certificate limit exceeded for subscription", "The certificate quota of the EZCA subscription is exceeded"},
+		{"api error: User is not allowed to request certificates from this template", "The current identity is not permitted"},
+		{"api error: invalid certificate request", ""},
+		{"api error: Too many requests, retry later", ""},
+		{"api error: Rate limit exceeded", ""},
+		{"api error: Key usage Certificate Sign is not allowed by the template", ""},
+		{"api error: Extended key usage 1.3.6.1.5.5.7.3.3 is not allowed", ""},
+	}
+	for _, tt := range tests {
+		detail := ezcaErrorDetail(errors.New(tt.err))
+		if tt.hint == "" {
+			require.Equal(t, tt.err, detail)
+			continue
+		}
+		require.True(t, strings.HasPrefix(detail, tt.err+"\n\n"), detail)
+		require.Contains(t, detail, tt.hint)
+	}
+}
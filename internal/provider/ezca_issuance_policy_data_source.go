@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &KeytosEzcaIssuancePolicyDataSource{}
+
+func NewKeytosEzcaIssuancePolicyDataSource() datasource.DataSource {
+	return &KeytosEzcaIssuancePolicyDataSource{}
+}
+
+// KeytosEzcaIssuancePolicyDataSource defines the data source implementation.
+type KeytosEzcaIssuancePolicyDataSource struct {
+	client *ezcaClient
+}
+
+// KeytosEzcaIssuancePolicyDataSourceModel describes the data source data model.
+type KeytosEzcaIssuancePolicyDataSourceModel struct {
+	AuthorityID     types.String `tfsdk:"authority_id"`
+	TemplateID      types.String `tfsdk:"template_id"`
+	CertificatesPEM types.List   `tfsdk:"certificates_pem"`
+	MaxValidityDays types.Int64  `tfsdk:"max_validity_days"`
+	MinRSAKeySize   types.Int64  `tfsdk:"min_rsa_key_size"`
+	ForbidSHA1      types.Bool   `tfsdk:"forbid_sha1"`
+	RequireSAN      types.Bool   `tfsdk:"require_subject_alternative_names"`
+
+	Compliant  types.Bool                     `tfsdk:"compliant"`
+	Violations []IssuancePolicyViolationModel `tfsdk:"violations"`
+}
+
+type IssuancePolicyViolationModel struct {
+	Rule         types.String `tfsdk:"rule"`
+	SerialNumber types.String `tfsdk:"serial_number"`
+	Subject      types.String `tfsdk:"subject"`
+	Message      types.String `tfsdk:"message"`
+}
+
+func (d *KeytosEzcaIssuancePolicyDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ezca_issuance_policy"
+}
+
+func (d *KeytosEzcaIssuancePolicyDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Checks an EZCA SSL authority template and certificates it issued against an organizational issuance policy, returning the violations for use in `check` blocks or conditions. EZCA does not list the certificates an authority issued, so the certificates to audit must be provided, for example from the `cert_pem` of certificate resources.",
+
+		Attributes: map[string]schema.Attribute{
+			"authority_id": schema.StringAttribute{
+				MarkdownDescription: "EZCA SSL authority identifier",
+				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
+			},
+			"template_id": schema.StringAttribute{
+				MarkdownDescription: "EZCA authority SSL template identifier",
+				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
+			},
+			"certificates_pem": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Certificates issued by the authority to audit, in PEM format",
+				Optional:            true,
+			},
+			"max_validity_days": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum validity period of certificates in days. Defaults to %d, a day under the %d day maximum of publicly trusted TLS certificates.", defaultMaxValidityDays, maxTLSServerValidityDays),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"min_rsa_key_size": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Minimum size of the RSA keys of certificates in bits. Defaults to %d, the minimum of publicly trusted TLS certificates.", defaultMinRSAKeySize),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"forbid_sha1": schema.BoolAttribute{
+				MarkdownDescription: "Whether the authority and certificates must not use SHA-1 signatures. Defaults to true.",
+				Optional:            true,
+			},
+			"require_subject_alternative_names": schema.BoolAttribute{
+				MarkdownDescription: "Whether certificates must have subject alternative names. Defaults to true.",
+				Optional:            true,
+			},
+
+			"compliant": schema.BoolAttribute{
+				MarkdownDescription: "Whether the authority and all certificates satisfy the policy",
+				Computed:            true,
+			},
+			"violations": schema.ListNestedAttribute{
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"rule": schema.StringAttribute{
+							MarkdownDescription: "Violated rule. One of `max_validity`, `min_rsa_key_size`, `no_sha1` or `require_subject_alternative_names`.",
+							Computed:            true,
+						},
+						"serial_number": schema.StringAttribute{
+							MarkdownDescription: "Serial number of the violating certificate. Null when the authority violates the rule.",
+							Computed:            true,
+						},
+						"subject": schema.StringAttribute{
+							MarkdownDescription: "Subject of the violating certificate. Null when the authority violates the rule.",
+							Computed:            true,
+						},
+						"message": schema.StringAttribute{
+							MarkdownDescription: "Description of the violation",
+							Computed:            true,
+						},
+					},
+				},
+				MarkdownDescription: "Violations of the policy, empty when compliant",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *KeytosEzcaIssuancePolicyDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*KeytosData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *KeytosData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.Client
+}
+
+func (d *KeytosEzcaIssuancePolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data KeytosEzcaIssuancePolicyDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	authorityId, err := uuid.Parse(data.AuthorityID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Authority ID", fmt.Sprintf("Expected a valid UUID for Authority ID, got %s: %v", data.AuthorityID.ValueString(), err))
+	}
+	templateId, err := uuid.Parse(data.TemplateID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Template ID", fmt.Sprintf("Expected a valid UUID for Template ID, got %s: %v", data.TemplateID.ValueString(), err))
+	}
+	var certsPEM []string
+	if !data.CertificatesPEM.IsNull() {
+		resp.Diagnostics.Append(data.CertificatesPEM.ElementsAs(ctx, &certsPEM, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy := defaultIssuancePolicy
+	if !data.MaxValidityDays.IsNull() {
+		policy.maxValidity = time.Duration(data.MaxValidityDays.ValueInt64()) * 24 * time.Hour
+	}
+	if !data.MinRSAKeySize.IsNull() {
+		policy.minRSAKeySize = int(data.MinRSAKeySize.ValueInt64())
+	}
+	if !data.ForbidSHA1.IsNull() {
+		policy.forbidSHA1 = data.ForbidSHA1.ValueBool()
+	}
+	if !data.RequireSAN.IsNull() {
+		policy.requireSAN = data.RequireSAN.ValueBool()
+	}
+
+	c, err := d.client.SSLAuthority(ctx, authorityId, templateId)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid SSL authority", fmt.Sprintf("Error validating SSL Authority: %s", ezcaErrorDetail(err)))
+		return
+	}
+	info, err := c.Info(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading SSL Authority", fmt.Sprintf("Error getting SSL authority information: %s", ezcaErrorDetail(err)))
+		return
+	}
+
+	data.Violations = []IssuancePolicyViolationModel{}
+	for _, v := range policy.checkAuthority(string(info.HashAlgorithm)) {
+		data.Violations = append(data.Violations, IssuancePolicyViolationModel{
+			Rule:         types.StringValue(v.rule),
+			SerialNumber: types.StringNull(),
+			Subject:      types.StringNull(),
+			Message:      types.StringValue(v.message),
+		})
+	}
+	for i, certPEM := range certsPEM {
+		cert, err := parseCertificatePEM(certPEM)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Certificate PEM", fmt.Sprintf("Error parsing certificate %d: %v", i, err))
+			continue
+		}
+		for _, v := range policy.checkCertificate(cert) {
+			data.Violations = append(data.Violations, IssuancePolicyViolationModel{
+				Rule:         types.StringValue(v.rule),
+				SerialNumber: types.StringValue(cert.SerialNumber.String()),
+				Subject:      types.StringValue(cert.Subject.String()),
+				Message:      types.StringValue(v.message),
+			})
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Compliant = types.BoolValue(len(data.Violations) == 0)
+
+	tflog.Trace(ctx, "read an issuance policy data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
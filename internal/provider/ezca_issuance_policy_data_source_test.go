@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/markeytos/terraform-provider-keytos/internal/acctest"
+)
+
+func TestAccKeytosEzcaIssuancePolicy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccKeytosEzcaIssuancePolicyConfig(0),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value`),
+			},
+			// Read testing
+			{
+				Config: testAccKeytosEzcaIssuancePolicyConfig(397),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_issuance_policy.test",
+						tfjsonpath.New("compliant"),
+						knownvalue.Bool(true),
+					),
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_issuance_policy.test",
+						tfjsonpath.New("violations"),
+						knownvalue.ListSizeExact(0),
+					),
+				},
+			},
+			{
+				Config: testAccKeytosEzcaIssuancePolicyConfig(1),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_issuance_policy.test",
+						tfjsonpath.New("compliant"),
+						knownvalue.Bool(false),
+					),
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_issuance_policy.test",
+						tfjsonpath.New("violations"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"rule": knownvalue.StringExact(policyRuleMaxValidity),
+							}),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccKeytosEzcaIssuancePolicyConfig(maxValidityDays int) string {
+	return fmt.Sprintf(`
+resource "keytos_ezca_ssl_leaf_cert" "test" {
+  authority_id = %[1]q
+  template_id = %[2]q
+  cert_request_pem = %[3]q
+  validity_period = "48h"
+  additional_subject_alternative_names = {
+    dns_names = ["test.com"]
+  }
+}
+
+data "keytos_ezca_issuance_policy" "test" {
+  authority_id = %[1]q
+  template_id = %[2]q
+  certificates_pem = [keytos_ezca_ssl_leaf_cert.test.cert_pem]
+  max_validity_days = %[4]d
+}
+`, test_authority_id, test_template_id, testCSR, maxValidityDays)
+}
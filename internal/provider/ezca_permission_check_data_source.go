@@ -0,0 +1,199 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	permissionOperationSign   = "sign"
+	permissionOperationRevoke = "revoke"
+	permissionOperationAdmin  = "admin"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &KeytosEzcaPermissionCheckDataSource{}
+
+func NewKeytosEzcaPermissionCheckDataSource() datasource.DataSource {
+	return &KeytosEzcaPermissionCheckDataSource{}
+}
+
+// KeytosEzcaPermissionCheckDataSource defines the data source implementation.
+type KeytosEzcaPermissionCheckDataSource struct {
+	client *ezcaClient
+}
+
+// KeytosEzcaPermissionCheckDataSourceModel describes the data source data model.
+type KeytosEzcaPermissionCheckDataSourceModel struct {
+	AuthorityID types.String `tfsdk:"authority_id"`
+	TemplateID  types.String `tfsdk:"template_id"`
+	Operation   types.String `tfsdk:"operation"`
+	Authorized  types.Bool   `tfsdk:"authorized"`
+	Message     types.String `tfsdk:"message"`
+}
+
+func (d *KeytosEzcaPermissionCheckDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ezca_permission_check"
+}
+
+func (d *KeytosEzcaPermissionCheckDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Checks whether the identity used by the provider is allowed to perform an operation on an EZCA SSL authority template. Use it to fail fast, for example with a `precondition`, before attempting issuance.",
+
+		Attributes: map[string]schema.Attribute{
+			"authority_id": schema.StringAttribute{
+				MarkdownDescription: "EZCA SSL authority identifier",
+				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
+			},
+			"template_id": schema.StringAttribute{
+				MarkdownDescription: "EZCA authority SSL template identifier",
+				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
+			},
+			"operation": schema.StringAttribute{
+				MarkdownDescription: "Operation to check. One of `sign` (request certificates from the template), `revoke` (revoke certificates issued from the template, allowed for requesters and administrators) or `admin` (administer the authority).",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(permissionOperationSign, permissionOperationRevoke, permissionOperationAdmin),
+				},
+			},
+
+			"authorized": schema.BoolAttribute{
+				MarkdownDescription: "Whether the current identity is authorized to perform the operation",
+				Computed:            true,
+			},
+			"message": schema.StringAttribute{
+				MarkdownDescription: "Explanation of the result, including guidance on how to get access when not authorized",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *KeytosEzcaPermissionCheckDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*KeytosData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *KeytosData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.Client
+}
+
+func (d *KeytosEzcaPermissionCheckDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data KeytosEzcaPermissionCheckDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	authorityId, err := uuid.Parse(data.AuthorityID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Authority ID", fmt.Sprintf("Expected a valid UUID for Authority ID, got %s: %v", data.AuthorityID.ValueString(), err))
+	}
+	templateId, err := uuid.Parse(data.TemplateID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Template ID", fmt.Sprintf("Expected a valid UUID for Template ID, got %s: %v", data.TemplateID.ValueString(), err))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var canSign, canAdmin bool
+	op := data.Operation.ValueString()
+	if op == permissionOperationSign || op == permissionOperationRevoke {
+		canSign, err = d.canSign(ctx, authorityId, templateId)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Checking Permissions", fmt.Sprintf("Error listing SSL authorities available to the current identity: %v", err))
+			return
+		}
+	}
+	if op == permissionOperationAdmin || (op == permissionOperationRevoke && !canSign) {
+		canAdmin, err = d.canAdmin(ctx, authorityId)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Checking Permissions", fmt.Sprintf("Error listing authorities administered by the current identity: %v", err))
+			return
+		}
+	}
+
+	switch op {
+	case permissionOperationSign:
+		data.Authorized = types.BoolValue(canSign)
+		if canSign {
+			data.Message = types.StringValue("The current identity can request certificates from the template.")
+		} else {
+			data.Message = types.StringValue(fmt.Sprintf("The current identity cannot request certificates from template %s of authority %s. Ask an administrator of the authority to add the identity as a requester of the template.", templateId, authorityId))
+		}
+	case permissionOperationRevoke:
+		data.Authorized = types.BoolValue(canSign || canAdmin)
+		if canSign || canAdmin {
+			data.Message = types.StringValue("The current identity can revoke certificates issued from the template.")
+		} else {
+			data.Message = types.StringValue(fmt.Sprintf("The current identity is neither a requester of template %s nor an administrator of authority %s, so it cannot revoke its certificates. Ask an administrator of the authority for access.", templateId, authorityId))
+		}
+	case permissionOperationAdmin:
+		data.Authorized = types.BoolValue(canAdmin)
+		if canAdmin {
+			data.Message = types.StringValue("The current identity administers the authority.")
+		} else {
+			data.Message = types.StringValue(fmt.Sprintf("The current identity does not administer authority %s. Ask an existing administrator of the authority to add it as an administrator.", authorityId))
+		}
+	}
+
+	tflog.Trace(ctx, "read a permission check data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *KeytosEzcaPermissionCheckDataSource) canSign(ctx context.Context, authorityId, templateId uuid.UUID) (bool, error) {
+	as, err := d.client.ListSSLAuthorities(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range as {
+		if a.ID == authorityId && a.TemplateID == templateId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *KeytosEzcaPermissionCheckDataSource) canAdmin(ctx context.Context, authorityId uuid.UUID) (bool, error) {
+	as, err := d.client.ListAuthorities(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range as {
+		if a.ID == authorityId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
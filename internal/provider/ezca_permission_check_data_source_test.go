@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/markeytos/terraform-provider-keytos/internal/acctest"
+)
+
+func TestAccKeytosEzcaPermissionCheck(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccKeytosEzcaPermissionCheckConfig("sign"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_permission_check.test",
+						tfjsonpath.New("operation"),
+						knownvalue.StringExact("sign"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_permission_check.test",
+						tfjsonpath.New("authorized"),
+						knownvalue.Bool(true),
+					),
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_permission_check.test",
+						tfjsonpath.New("message"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			{
+				Config: testAccKeytosEzcaPermissionCheckConfig("revoke"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_permission_check.test",
+						tfjsonpath.New("authorized"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+			// Validation testing
+			{
+				Config:      testAccKeytosEzcaPermissionCheckConfig("delete"),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+		},
+	})
+}
+
+func testAccKeytosEzcaPermissionCheckConfig(operation string) string {
+	return fmt.Sprintf(`
+data "keytos_ezca_permission_check" "test" {
+  authority_id = %q
+  template_id = %q
+  operation = %q
+}
+`, test_authority_id, test_template_id, operation)
+}
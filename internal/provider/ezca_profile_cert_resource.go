@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/markeytos/ezca-go"
+)
+
+// certProfile fixes the usages of the certificates of a purpose-built
+// resource.
+type certProfile struct {
+	// typeName is the resource type name without the provider prefix.
+	typeName          string
+	description       string
+	keyUsages         []ezca.KeyUsage
+	extendedKeyUsages []ezca.ExtKeyUsage
+	// requireDNSName requires the certificate to have a DNS name, for clients
+	// that validate the certificate against the name of the server.
+	requireDNSName bool
+}
+
+var codeSigningCertProfile = certProfile{
+	typeName: "_ezca_code_signing_cert",
+	description: "Creates a code signing certificate that is issued by an EZCA SSL authority, whose template must allow the code signing extended key usage, for build pipelines that sign artifacts. " +
+		"Create the certificate request with a key kept in Azure Key Vault or an HSM, as code signing keys should not be exportable. " +
+		"Timestamp signatures with an RFC 3161 timestamp authority so that they remain valid after the certificate expires or is revoked. " +
+		"If the resource is deleted prior to expiration, it will be revoked, which invalidates signatures that were not timestamped.",
+	keyUsages:         []ezca.KeyUsage{ezca.KeyUsageDigitalSignature},
+	extendedKeyUsages: []ezca.ExtKeyUsage{ezca.ExtKeyUsageCodeSigning},
+}
+
+var radiusServerCertProfile = certProfile{
+	typeName: "_ezca_radius_server_cert",
+	description: "Creates a RADIUS server certificate that is issued by an EZCA SSL authority, for NPS or other RADIUS servers authenticating WiFi and wired clients with EAP-TLS or PEAP. " +
+		"The certificate must have a DNS name, either from the certificate request or `additional_subject_alternative_names`, which supplicants are configured to match. " +
+		"Supplicants are usually configured to trust the root of the authority rather than the server certificate, so renewals do not require reconfiguring them as long as the root does not change; issue from an authority whose root outlives the deployed supplicant profiles. " +
+		"If the resource is deleted prior to expiration, it will be revoked.",
+	keyUsages:         []ezca.KeyUsage{ezca.KeyUsageDigitalSignature, ezca.KeyUsageKeyEncipherment},
+	extendedKeyUsages: []ezca.ExtKeyUsage{ezca.ExtKeyUsageServerAuth},
+	requireDNSName:    true,
+}
+
+var timestampingCertProfile = certProfile{
+	typeName: "_ezca_timestamping_cert",
+	description: "Creates a timestamping certificate that is issued by an EZCA SSL authority, whose template must allow the timestamping extended key usage, for internal RFC 3161 timestamp authorities. " +
+		"Timestamps are verified against the certificate long after they are issued, so choose a long `validity_period` and renew with `early_renewal_period` well before expiration, and keep the previous certificates available to verifiers. " +
+		"EZCA decides the criticality of the extended key usage extension, which RFC 3161 requires to be critical, so check the issued certificate against the verifiers in use. " +
+		"If the resource is deleted prior to expiration, it will be revoked, which invalidates its timestamps for verifiers checking revocation.",
+	keyUsages:         []ezca.KeyUsage{ezca.KeyUsageDigitalSignature},
+	extendedKeyUsages: []ezca.ExtKeyUsage{ezca.ExtKeyUsageTimeStamping},
+}
+
+var ocspResponderCertProfile = certProfile{
+	typeName: "_ezca_ocsp_responder_cert",
+	description: "Creates a delegated OCSP responder signing certificate that is issued by an EZCA SSL authority, whose template must allow the OCSP signing extended key usage, for responders answering for the certificates of that authority. " +
+		"Clients only accept responses signed by a certificate issued directly by the authority of the certificate being checked, so use one resource per issuing authority. " +
+		"EZCA does not add the OCSP no check extension, so clients may check the revocation of the responder certificate itself; keep `validity_period` short and renew often with `early_renewal_period`. " +
+		"If the resource is deleted prior to expiration, it will be revoked.",
+	keyUsages:         []ezca.KeyUsage{ezca.KeyUsageDigitalSignature},
+	extendedKeyUsages: []ezca.ExtKeyUsage{ezca.ExtKeyUsageOCSPSigning},
+}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &KeytosEzcaProfileCertResource{}
+var _ resource.ResourceWithModifyPlan = &KeytosEzcaProfileCertResource{}
+var _ resource.ResourceWithValidateConfig = &KeytosEzcaProfileCertResource{}
+
+func NewKeytosEzcaCodeSigningCertResource() resource.Resource {
+	return &KeytosEzcaProfileCertResource{profile: codeSigningCertProfile}
+}
+
+func NewKeytosEzcaRadiusServerCertResource() resource.Resource {
+	return &KeytosEzcaProfileCertResource{profile: radiusServerCertProfile}
+}
+
+func NewKeytosEzcaTimestampingCertResource() resource.Resource {
+	return &KeytosEzcaProfileCertResource{profile: timestampingCertProfile}
+}
+
+func NewKeytosEzcaOcspResponderCertResource() resource.Resource {
+	return &KeytosEzcaProfileCertResource{profile: ocspResponderCertProfile}
+}
+
+// KeytosEzcaProfileCertResource defines the resource implementation. It
+// manages the certificate like KeytosEzcaSslLeafCertResource, with the key
+// usages and extended key usages of its profile.
+type KeytosEzcaProfileCertResource struct {
+	KeytosEzcaSslLeafCertResource
+
+	profile certProfile
+}
+
+func (r *KeytosEzcaProfileCertResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.profile.typeName
+}
+
+func (r *KeytosEzcaProfileCertResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	r.KeytosEzcaSslLeafCertResource.Schema(ctx, req, resp)
+	resp.Schema.MarkdownDescription = r.profile.description
+
+	kus := make([]attr.Value, len(r.profile.keyUsages))
+	for i, ku := range r.profile.keyUsages {
+		kus[i] = types.StringValue(string(ku))
+	}
+	resp.Schema.Attributes["key_usages"] = schema.SetAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: "Key usages of the certificate, always " + quotedList(profileStrings(r.profile.keyUsages)) + ".",
+		Computed:            true,
+		Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, kus)),
+	}
+	ekus := make([]attr.Value, len(r.profile.extendedKeyUsages))
+	for i, eku := range r.profile.extendedKeyUsages {
+		ekus[i] = types.StringValue(string(eku))
+	}
+	resp.Schema.Attributes["extended_key_usages"] = schema.SetAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: "Extended key usages of the certificate as object identifiers in dotted notation, always " + quotedList(profileStrings(r.profile.extendedKeyUsages)) + ".",
+		Computed:            true,
+		Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, ekus)),
+	}
+	resp.Schema.Attributes["forward_request_usages"] = schema.BoolAttribute{
+		MarkdownDescription: "Always false, as the usages of the certificate are fixed.",
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+	}
+}
+
+func (r *KeytosEzcaProfileCertResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	r.KeytosEzcaSslLeafCertResource.ValidateConfig(ctx, req, resp)
+	if r.profile.requireDNSName {
+		checkDNSName(ctx, req.Config, &resp.Diagnostics)
+	}
+}
+
+// checkDNSName validates that the certificate request or the additional
+// subject alternative names have a DNS name.
+func checkDNSName(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var sans types.Object
+	var csrPEM, csrDERBase64 types.String
+	diags.Append(config.GetAttribute(ctx, path.Root("additional_subject_alternative_names"), &sans)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("cert_request_pem"), &csrPEM)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("cert_request_der_base64"), &csrDERBase64)...)
+	if diags.HasError() || sans.IsUnknown() || csrPEM.IsUnknown() || csrDERBase64.IsUnknown() {
+		return
+	}
+	if !sans.IsNull() {
+		if dnsNames, ok := sans.Attributes()["dns_names"].(types.List); ok && (dnsNames.IsUnknown() || len(dnsNames.Elements()) > 0) {
+			return
+		}
+	}
+	var der []byte
+	var err error
+	switch {
+	case !csrPEM.IsNull():
+		der, err = csr(csrPEM.ValueString())
+	case !csrDERBase64.IsNull():
+		der, err = base64.StdEncoding.DecodeString(csrDERBase64.ValueString())
+	default:
+		return
+	}
+	if err != nil {
+		// Reported when planning
+		return
+	}
+	if req, err := x509.ParseCertificateRequest(der); err != nil || len(req.DNSNames) > 0 {
+		return
+	}
+	diags.AddAttributeError(
+		path.Root("additional_subject_alternative_names").AtName("dns_names"),
+		"Missing DNS Name",
+		"Clients validate the certificate against the name of the server, but neither the certificate request nor additional_subject_alternative_names has a DNS name.",
+	)
+}
+
+func profileStrings[T ~string](values []T) []string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = string(v)
+	}
+	return s
+}
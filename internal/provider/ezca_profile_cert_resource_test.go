@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/markeytos/ezca-go"
+	"github.com/markeytos/terraform-provider-keytos/internal/acctest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccKeytosEzcaCodeSigningCert(t *testing.T) {
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			// Validation testing
+			{
+				Config:      testAccKeytosEzcaProfileCertConfig("keytos_ezca_code_signing_cert", `  extended_key_usages = ["1.3.6.1.5.5.7.3.1"]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Configuration for Read-Only Attribute`),
+			},
+			// Create and Read testing
+			{
+				Config: testAccKeytosEzcaProfileCertConfig("keytos_ezca_code_signing_cert", ""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_code_signing_cert.test",
+						tfjsonpath.New("extended_key_usages"),
+						knownvalue.SetExact([]knownvalue.Check{knownvalue.StringExact(string(ezca.ExtKeyUsageCodeSigning))}),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_code_signing_cert.test",
+						tfjsonpath.New("key_usages"),
+						knownvalue.SetExact([]knownvalue.Check{knownvalue.StringExact(string(ezca.KeyUsageDigitalSignature))}),
+					),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccKeytosEzcaRadiusServerCert(t *testing.T) {
+	dnsNames := `  additional_subject_alternative_names = {
+    dns_names = ["radius.example.com"]
+  }`
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			// Validation testing
+			{
+				Config:      testAccKeytosEzcaProfileCertConfig("keytos_ezca_radius_server_cert", ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Missing DNS Name`),
+			},
+			// Create and Read testing
+			{
+				Config: testAccKeytosEzcaProfileCertConfig("keytos_ezca_radius_server_cert", dnsNames),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_radius_server_cert.test",
+						tfjsonpath.New("extended_key_usages"),
+						knownvalue.SetExact([]knownvalue.Check{knownvalue.StringExact(string(ezca.ExtKeyUsageServerAuth))}),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_radius_server_cert.test",
+						tfjsonpath.New("issued_dns_names"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("radius.example.com")}),
+					),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccKeytosEzcaTimestampingCert(t *testing.T) {
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccKeytosEzcaProfileCertConfig("keytos_ezca_timestamping_cert", ""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_timestamping_cert.test",
+						tfjsonpath.New("extended_key_usages"),
+						knownvalue.SetExact([]knownvalue.Check{knownvalue.StringExact(string(ezca.ExtKeyUsageTimeStamping))}),
+					),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccKeytosEzcaOcspResponderCert(t *testing.T) {
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccKeytosEzcaProfileCertConfig("keytos_ezca_ocsp_responder_cert", ""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ocsp_responder_cert.test",
+						tfjsonpath.New("extended_key_usages"),
+						knownvalue.SetExact([]knownvalue.Check{knownvalue.StringExact(string(ezca.ExtKeyUsageOCSPSigning))}),
+					),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccKeytosEzcaProfileCertConfig(typeName, extra string) string {
+	return fmt.Sprintf(`
+resource %q "test" {
+  authority_id = %q
+  template_id = %q
+  cert_request_pem = %q
+  validity_period = "24h"
+%s
+}
+`, typeName, test_authority_id, test_template_id, testCSR, extra)
+}
+
+func TestCodeSigningCertSchema(t *testing.T) {
+	ctx := context.Background()
+	r := NewKeytosEzcaCodeSigningCertResource()
+	var resp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	require.False(t, resp.Schema.ValidateImplementation(ctx).HasError())
+
+	usages := func(name string) types.Set {
+		a, ok := resp.Schema.Attributes[name].(schema.SetAttribute)
+		require.True(t, ok, name)
+		require.False(t, a.Optional, name)
+		var defaultResp defaults.SetResponse
+		a.Default.DefaultSet(ctx, defaults.SetRequest{}, &defaultResp)
+		return defaultResp.PlanValue
+	}
+	m := KeytosEzcaSslLeafCertResourceModel{
+		CertRequestPEM:                    types.StringValue(testCSR),
+		ValidityPeriod:                    durationString("24h"),
+		KeyUsages:                         usages("key_usages"),
+		ExtendedKeyUsages:                 usages("extended_key_usages"),
+		ForwardRequestUsages:              types.BoolValue(false),
+		OverwriteSubjectName:              types.ObjectUnknown(subjectNameAttributeTypes),
+		OverwriteSubjectNameStr:           distinguishedNameUnknown(),
+		AdditionalSubjectAlternativeNames: types.ObjectUnknown(subjectAlternativeNamesAttributeTypes),
+	}
+	var diags diag.Diagnostics
+	opts := buildSignOptions(ctx, &m, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, []ezca.KeyUsage{ezca.KeyUsageDigitalSignature}, opts.KeyUsages)
+	require.Equal(t, []ezca.ExtKeyUsage{ezca.ExtKeyUsageCodeSigning}, opts.ExtendedKeyUsages)
+}
@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const defaultRenewalForecastDays = 30
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &KeytosEzcaRenewalForecastDataSource{}
+
+func NewKeytosEzcaRenewalForecastDataSource() datasource.DataSource {
+	return &KeytosEzcaRenewalForecastDataSource{}
+}
+
+// KeytosEzcaRenewalForecastDataSource defines the data source implementation.
+type KeytosEzcaRenewalForecastDataSource struct{}
+
+// KeytosEzcaRenewalForecastDataSourceModel describes the data source data model.
+type KeytosEzcaRenewalForecastDataSourceModel struct {
+	Certificates []RenewalForecastCertificateModel `tfsdk:"certificates"`
+	WithinDays   types.Int64                       `tfsdk:"within_days"`
+
+	Renewals    []RenewalForecastRenewalModel `tfsdk:"renewals"`
+	NextRenewal types.String                  `tfsdk:"next_renewal"`
+}
+
+type RenewalForecastCertificateModel struct {
+	Name               types.String  `tfsdk:"name"`
+	ValidityNotBefore  types.String  `tfsdk:"validity_not_before"`
+	ValidityNotAfter   types.String  `tfsdk:"validity_not_after"`
+	EarlyRenewalPeriod durationValue `tfsdk:"early_renewal_period"`
+	RenewBeforePercent types.Int64   `tfsdk:"renew_before_percent"`
+	RotationSchedule   durationValue `tfsdk:"rotation_schedule"`
+}
+
+type RenewalForecastRenewalModel struct {
+	Name             types.String `tfsdk:"name"`
+	ValidityNotAfter types.String `tfsdk:"validity_not_after"`
+	RenewalTime      types.String `tfsdk:"renewal_time"`
+	DaysUntilRenewal types.Int64  `tfsdk:"days_until_renewal"`
+}
+
+func (d *KeytosEzcaRenewalForecastDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ezca_renewal_forecast"
+}
+
+func (d *KeytosEzcaRenewalForecastDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Forecasts which certificates managed by the configuration will be renewed within a number of days, so that maintenance windows can be scheduled from plan output. Data sources cannot read the state of resources, so the certificates must be provided, typically from the `validity_not_before`, `validity_not_after`, `early_renewal_period`, `renew_before_percent` and `rotation_schedule` attributes of certificate resources.",
+
+		Attributes: map[string]schema.Attribute{
+			"certificates": schema.ListNestedAttribute{
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name identifying the certificate in the forecast, such as the resource address",
+							Required:            true,
+						},
+						"validity_not_before": schema.StringAttribute{
+							MarkdownDescription: "Validity start time stamp of the certificate as an RFC3339 timestamp, required by `renew_before_percent` and `rotation_schedule`",
+							Optional:            true,
+							Validators: []validator.String{
+								timestampValidator{},
+							},
+						},
+						"validity_not_after": schema.StringAttribute{
+							MarkdownDescription: "Expiration time stamp of the certificate as an RFC3339 timestamp",
+							Required:            true,
+						},
+						"early_renewal_period": schema.StringAttribute{
+							CustomType:          durationType{},
+							MarkdownDescription: "Early renewal period of the certificate resource",
+							Optional:            true,
+						},
+						"renew_before_percent": schema.Int64Attribute{
+							MarkdownDescription: "Renewal percentage of the certificate resource. Conflicts with `early_renewal_period`.",
+							Optional:            true,
+							Validators: []validator.Int64{
+								int64validator.Between(1, 99),
+								int64validator.ConflictsWith(path.MatchRelative().AtParent().AtName("early_renewal_period")),
+								int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("validity_not_before")),
+							},
+						},
+						"rotation_schedule": schema.StringAttribute{
+							CustomType:          durationType{},
+							MarkdownDescription: "Rotation schedule of the certificate resource",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("validity_not_before")),
+							},
+						},
+					},
+				},
+				MarkdownDescription: "Certificates to forecast the renewal of",
+				Required:            true,
+			},
+			"within_days": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Number of days to forecast renewals for. Defaults to %d.", defaultRenewalForecastDays),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+
+			"renewals": schema.ListNestedAttribute{
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the certificate",
+							Computed:            true,
+						},
+						"validity_not_after": schema.StringAttribute{
+							MarkdownDescription: "Expiration time stamp of the certificate as an RFC3339 timestamp",
+							Computed:            true,
+						},
+						"renewal_time": schema.StringAttribute{
+							MarkdownDescription: "Time from which the certificate is ready for renewal as an RFC3339 timestamp, renewed by the next apply",
+							Computed:            true,
+						},
+						"days_until_renewal": schema.Int64Attribute{
+							MarkdownDescription: "Full days until the certificate is ready for renewal, 0 when it already is",
+							Computed:            true,
+						},
+					},
+				},
+				MarkdownDescription: "Certificates ready for renewal within `within_days`, earliest first",
+				Computed:            true,
+			},
+			"next_renewal": schema.StringAttribute{
+				MarkdownDescription: "Earliest renewal time of the forecast as an RFC3339 timestamp. Null when no certificate will be renewed.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *KeytosEzcaRenewalForecastDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data KeytosEzcaRenewalForecastDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	days := int64(defaultRenewalForecastDays)
+	if !data.WithinDays.IsNull() {
+		days = data.WithinDays.ValueInt64()
+	}
+	now := time.Now()
+	horizon := now.Add(time.Duration(days) * 24 * time.Hour)
+
+	data.Renewals = []RenewalForecastRenewalModel{}
+	for i, c := range data.Certificates {
+		notAfter, err := time.Parse(time.RFC3339, c.ValidityNotAfter.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("certificates").AtListIndex(i).AtName("validity_not_after"),
+				"Invalid Expiration Time Stamp",
+				fmt.Sprintf("Expected an RFC3339 timestamp, got %q: %v", c.ValidityNotAfter.ValueString(), err),
+			)
+			continue
+		}
+		erp, err := parseEarlyRenewalPeriod(c.EarlyRenewalPeriod)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("certificates").AtListIndex(i).AtName("early_renewal_period"),
+				"Invalid Early Renewal Period",
+				fmt.Sprintf("Invalid duration string: %v", err),
+			)
+			continue
+		}
+
+		if !c.ValidityNotBefore.IsNull() {
+			notBefore, err := time.Parse(time.RFC3339, c.ValidityNotBefore.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("certificates").AtListIndex(i).AtName("validity_not_before"),
+					"Invalid Validity Start Time Stamp",
+					fmt.Sprintf("Expected an RFC3339 timestamp, got %q: %v", c.ValidityNotBefore.ValueString(), err),
+				)
+				continue
+			}
+			erp = renewalPeriod(erp, c.RenewBeforePercent, c.RotationSchedule, notBefore, notAfter)
+		}
+
+		renewal := notAfter.Add(-erp).UTC()
+		if renewal.After(horizon) {
+			continue
+		}
+		data.Renewals = append(data.Renewals, RenewalForecastRenewalModel{
+			Name:             c.Name,
+			ValidityNotAfter: c.ValidityNotAfter,
+			RenewalTime:      types.StringValue(renewal.Format(time.RFC3339)),
+			DaysUntilRenewal: types.Int64Value(daysUntil(now, renewal)),
+		})
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// UTC RFC3339 time stamps sort chronologically
+	sort.SliceStable(data.Renewals, func(i, j int) bool {
+		return data.Renewals[i].RenewalTime.ValueString() < data.Renewals[j].RenewalTime.ValueString()
+	})
+	data.NextRenewal = types.StringNull()
+	if len(data.Renewals) > 0 {
+		data.NextRenewal = data.Renewals[0].RenewalTime
+	}
+
+	tflog.Trace(ctx, "read a renewal forecast data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// daysUntil returns the full days from now until t, 0 when t is past.
+func daysUntil(now, t time.Time) int64 {
+	if !t.After(now) {
+		return 0
+	}
+	return int64(t.Sub(now) / (24 * time.Hour))
+}
@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/markeytos/terraform-provider-keytos/internal/acctest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccKeytosEzcaRenewalForecast(t *testing.T) {
+	now := time.Now().UTC()
+	soon := now.Add(10*24*time.Hour + time.Hour).Format(time.RFC3339)
+	later := now.Add(90*24*time.Hour + time.Hour).Format(time.RFC3339)
+	expired := now.Add(-time.Hour).Format(time.RFC3339)
+	issued := now.Add(-20 * 24 * time.Hour).Format(time.RFC3339)
+	expires := now.Add(70*24*time.Hour + time.Hour).Format(time.RFC3339)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccKeytosEzcaRenewalForecastConfig("not a time stamp", later, "null"),
+				ExpectError: regexp.MustCompile(`Invalid Expiration Time Stamp`),
+			},
+			// Read testing
+			{
+				Config: testAccKeytosEzcaRenewalForecastConfig(soon, later, "null"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_renewal_forecast.test",
+						tfjsonpath.New("renewals"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectExact(map[string]knownvalue.Check{
+								"name":               knownvalue.StringExact("soon"),
+								"validity_not_after": knownvalue.StringExact(soon),
+								"renewal_time":       knownvalue.StringExact(soon),
+								"days_until_renewal": knownvalue.Int64Exact(10),
+							}),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_renewal_forecast.test",
+						tfjsonpath.New("next_renewal"),
+						knownvalue.StringExact(soon),
+					),
+				},
+			},
+			// Early renewal period and overdue renewals
+			{
+				Config: testAccKeytosEzcaRenewalForecastConfig(expired, later, `"1464h"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_renewal_forecast.test",
+						tfjsonpath.New("renewals"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"name":               knownvalue.StringExact("soon"),
+								"days_until_renewal": knownvalue.Int64Exact(0),
+							}),
+							knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"name":               knownvalue.StringExact("later"),
+								"days_until_renewal": knownvalue.Int64Exact(29),
+							}),
+						}),
+					),
+				},
+			},
+			// Lifetime-relative renewals
+			{
+				Config:      testAccKeytosEzcaRenewalForecastLifetimeConfig(`renew_before_percent = 50`, "", expires),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config: testAccKeytosEzcaRenewalForecastLifetimeConfig(`renew_before_percent = 50`, issued, expires),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_renewal_forecast.test",
+						tfjsonpath.New("renewals"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"days_until_renewal": knownvalue.Int64Exact(25),
+							}),
+						}),
+					),
+				},
+			},
+			{
+				Config: testAccKeytosEzcaRenewalForecastLifetimeConfig(`renew_before_percent = 50
+      rotation_schedule    = "30d"`, issued, expires),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.keytos_ezca_renewal_forecast.test",
+						tfjsonpath.New("renewals"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"days_until_renewal": knownvalue.Int64Exact(9),
+							}),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestDaysUntil(t *testing.T) {
+	now := time.Now()
+	require.EqualValues(t, 0, daysUntil(now, now.Add(-time.Hour)))
+	require.EqualValues(t, 0, daysUntil(now, now.Add(23*time.Hour)))
+	require.EqualValues(t, 1, daysUntil(now, now.Add(25*time.Hour)))
+}
+
+func testAccKeytosEzcaRenewalForecastConfig(soon, later, laterEarlyRenewal string) string {
+	return fmt.Sprintf(`
+data "keytos_ezca_renewal_forecast" "test" {
+  certificates = [
+    {
+      name               = "later"
+      validity_not_after = %q
+      early_renewal_period = %s
+    },
+    {
+      name               = "soon"
+      validity_not_after = %q
+    },
+  ]
+}
+`, later, laterEarlyRenewal, soon)
+}
+
+func testAccKeytosEzcaRenewalForecastLifetimeConfig(renewal, notBefore, notAfter string) string {
+	validityNotBefore := "null"
+	if notBefore != "" {
+		validityNotBefore = fmt.Sprintf("%q", notBefore)
+	}
+	return fmt.Sprintf(`
+data "keytos_ezca_renewal_forecast" "test" {
+  certificates = [
+    {
+      name                 = "rotated"
+      validity_not_before  = %s
+      validity_not_after   = %q
+      %s
+    },
+  ]
+}
+`, validityNotBefore, notAfter, renewal)
+}
@@ -11,9 +11,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/markeytos/ezca-go"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -25,7 +25,7 @@ func NewKeytosEzcaSslAuthorityDataSource() datasource.DataSource {
 
 // KeytosEzcaSslAuthorityDataSource defines the data source implementation.
 type KeytosEzcaSslAuthorityDataSource struct {
-	client *ezca.Client
+	client *ezcaClient
 }
 
 // KeytosEzcaSslAuthorityModel describes the data source data model.
@@ -53,10 +53,16 @@ func (d *KeytosEzcaSslAuthorityDataSource) Schema(ctx context.Context, req datas
 			"authority_id": schema.StringAttribute{
 				MarkdownDescription: "EZCA SSL authority identifier",
 				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
 			},
 			"template_id": schema.StringAttribute{
 				MarkdownDescription: "EZCA authority SSL template identifier",
 				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
 			},
 
 			"key_type": schema.StringAttribute{
@@ -99,7 +105,7 @@ func (d *KeytosEzcaSslAuthorityDataSource) Configure(ctx context.Context, req da
 		return
 	}
 
-	client, ok := req.ProviderData.(*ezca.Client)
+	data, ok := req.ProviderData.(*KeytosData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -109,7 +115,7 @@ func (d *KeytosEzcaSslAuthorityDataSource) Configure(ctx context.Context, req da
 		return
 	}
 
-	d.client = client
+	d.client = data.Client
 }
 
 func (d *KeytosEzcaSslAuthorityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -132,15 +138,15 @@ func (d *KeytosEzcaSslAuthorityDataSource) Read(ctx context.Context, req datasou
 		return
 	}
 
-	c, err := ezca.NewSSLAuthorityClient(ctx, d.client, authorityId, templateId)
+	c, err := d.client.SSLAuthority(ctx, authorityId, templateId)
 	if err != nil {
-		resp.Diagnostics.AddError("Invalid SSL authority", fmt.Sprintf("Error validating SSL Authority: %v", err))
+		resp.Diagnostics.AddError("Invalid SSL authority", fmt.Sprintf("Error validating SSL Authority: %s", ezcaErrorDetail(err)))
 		return
 	}
 
 	info, err := c.Info(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("Invalid SSL authority", fmt.Sprintf("Error getting SSL Authority information: %v", err))
+		resp.Diagnostics.AddError("Invalid SSL authority", fmt.Sprintf("Error getting SSL Authority information: %s", ezcaErrorDetail(err)))
 		return
 	}
 
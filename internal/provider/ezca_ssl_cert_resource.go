@@ -0,0 +1,370 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	keyAlgorithmRSA2048   = "RSA-2048"
+	keyAlgorithmRSA3072   = "RSA-3072"
+	keyAlgorithmRSA4096   = "RSA-4096"
+	keyAlgorithmECDSAP256 = "ECDSA-P256"
+	keyAlgorithmECDSAP384 = "ECDSA-P384"
+	keyAlgorithmECDSAP521 = "ECDSA-P521"
+	keyAlgorithmEd25519   = "Ed25519"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &KeytosEzcaSslCertResource{}
+var _ resource.ResourceWithModifyPlan = &KeytosEzcaSslCertResource{}
+var _ resource.ResourceWithValidateConfig = &KeytosEzcaSslCertResource{}
+var _ resource.ResourceWithUpgradeState = &KeytosEzcaSslCertResource{}
+
+func NewKeytosEzcaSslCertResource() resource.Resource {
+	return &KeytosEzcaSslCertResource{}
+}
+
+// KeytosEzcaSslCertResource defines the resource implementation. It manages
+// the certificate like KeytosEzcaSslLeafCertResource, for a key pair and
+// certificate request it generates itself.
+type KeytosEzcaSslCertResource struct {
+	KeytosEzcaSslLeafCertResource
+}
+
+// KeytosEzcaSslCertResourceModel describes the resource data model.
+type KeytosEzcaSslCertResourceModel struct {
+	KeytosEzcaSslLeafCertResourceModel
+
+	KeyAlgorithm      types.String `tfsdk:"key_algorithm"`
+	PrivateKeyPEM     types.String `tfsdk:"private_key_pem"`
+	PrivateKeyPEMWO   types.String `tfsdk:"private_key_pem_wo"`
+	PrivateKeyVersion types.Int64  `tfsdk:"private_key_version"`
+	KeyVaultKeyID     types.String `tfsdk:"key_vault_key_id"`
+}
+
+func (r *KeytosEzcaSslCertResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ezca_ssl_cert"
+}
+
+func (r *KeytosEzcaSslCertResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := sslCertSchemaAttributes()
+	attributes["key_algorithm"] = schema.StringAttribute{
+		MarkdownDescription: "Algorithm of the generated key pair. One of `RSA-2048`, `RSA-3072`, `RSA-4096`, `ECDSA-P256`, `ECDSA-P384`, `ECDSA-P521` or `Ed25519`. Changing it generates a new key pair and certificate. Exactly one of `key_algorithm`, `private_key_pem_wo` or `key_vault_key_id` must be set.",
+		Optional:            true,
+		Validators: []validator.String{
+			stringvalidator.OneOf(
+				keyAlgorithmRSA2048, keyAlgorithmRSA3072, keyAlgorithmRSA4096,
+				keyAlgorithmECDSAP256, keyAlgorithmECDSAP384, keyAlgorithmECDSAP521,
+				keyAlgorithmEd25519,
+			),
+			stringvalidator.ExactlyOneOf(path.MatchRoot("private_key_pem_wo"), path.MatchRoot("key_vault_key_id")),
+		},
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+	attributes["private_key_pem"] = schema.StringAttribute{
+		MarkdownDescription: "Generated private key in PKCS #8 PEM format. This value is stored in the Terraform state. Null when the key is provided with `private_key_pem_wo` or `key_vault_key_id`.",
+		Computed:            true,
+		Sensitive:           true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+	attributes["private_key_pem_wo"] = schema.StringAttribute{
+		MarkdownDescription: "Private key in PEM format to create the certificate request with, instead of generating one. PKCS #8, PKCS #1 and SEC 1 keys are supported. The key is write-only and never stored in the Terraform state, which requires Terraform 1.11 or later. Change `private_key_version` to use a new key.",
+		Optional:            true,
+		WriteOnly:           true,
+		Sensitive:           true,
+	}
+	attributes["private_key_version"] = schema.Int64Attribute{
+		MarkdownDescription: "Version of the key provided with `private_key_pem_wo`. As write-only values are not stored, changing this is what triggers a new certificate request and certificate for the current key.",
+		Optional:            true,
+		Validators: []validator.Int64{
+			int64validator.AlsoRequires(path.MatchRoot("private_key_pem_wo")),
+		},
+		PlanModifiers: []planmodifier.Int64{
+			int64planmodifier.RequiresReplace(),
+		},
+	}
+	attributes["key_vault_key_id"] = schema.StringAttribute{
+		MarkdownDescription: "Identifier of an Azure Key Vault RSA or elliptic curve key to create the certificate request with, such as `https://example.vault.azure.net/keys/name/version`, instead of generating a key pair. The private key never leaves the vault, the provider credential must be allowed to get the key and sign with it. Without a version, the current version of the key is used. `pkcs12_base64` then only holds the certificates.",
+		Optional:            true,
+		Validators: []validator.String{
+			stringvalidator.RegexMatches(regexp.MustCompile(`^https://[^/]+/keys/[^/]+(/[^/]+)?$`), "must be an Azure Key Vault key identifier"),
+		},
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+	attributes["cert_request_pem"] = schema.StringAttribute{
+		MarkdownDescription: "Generated certificate request in PEM format. The request has an empty subject, set `overwrite_subject_name`, `overwrite_subject_name_str` or `additional_subject_alternative_names` to define the identity of the certificate.",
+		Computed:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+	attributes["cert_request_der_base64"] = schema.StringAttribute{
+		MarkdownDescription: "Generated certificate request in DER format, base64 encoded.",
+		Computed:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Generates a key pair and creates a certificate for it that is issued by an EZCA SSL authority. If the resource is deleted prior to expiration, it will be revoked.",
+		Version:             sslCertSchemaVersion,
+		Blocks:              sslCertSchemaBlocks(ctx),
+
+		Attributes: attributes,
+	}
+}
+
+func (r *KeytosEzcaSslCertResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data KeytosEzcaSslCertResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	data.sourceTag = sourceTag(ctx, req.ProviderMeta, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only values are only available from the configuration
+	var keyPEMWO types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("private_key_pem_wo"), &keyPEMWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var key crypto.Signer
+	var err error
+	if !keyPEMWO.IsNull() {
+		key, err = parsePrivateKeyPEM(keyPEMWO.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("private_key_pem_wo"), "Invalid Private Key PEM", fmt.Sprintf("Could not parse private key: %v", err))
+			return
+		}
+		data.PrivateKeyPEM = types.StringNull()
+	} else if !data.KeyVaultKeyID.IsNull() {
+		key, err = newKeyVaultSigner(ctx, r.credential, data.KeyVaultKeyID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("key_vault_key_id"), "Error Getting Key Vault Key", fmt.Sprintf("Could not get Key Vault key: %v", err))
+			return
+		}
+		data.PrivateKeyPEM = types.StringNull()
+	} else {
+		key, err = generatePrivateKey(data.KeyAlgorithm.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error Generating Private Key", fmt.Sprintf("Could not generate %s private key: %v", data.KeyAlgorithm.ValueString(), err))
+			return
+		}
+		keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Generating Private Key", fmt.Sprintf("Could not encode private key: %v", err))
+			return
+		}
+		data.PrivateKeyPEM = types.StringValue(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})))
+		data.privateKey = key
+	}
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Generating Certificate Request", fmt.Sprintf("Could not create certificate request: %v", err))
+		return
+	}
+	data.CertRequestPEM = types.StringValue(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})))
+	data.CertRequestDER = types.StringValue(base64.StdEncoding.EncodeToString(csrDER))
+	tflog.Trace(ctx, "generated private key and certificate request")
+
+	r.create(ctx, &data.KeytosEzcaSslLeafCertResourceModel, &resp.Diagnostics)
+	// A certificate may have been issued even when other errors occurred,
+	// in which case it must still be saved, the resource being tainted
+	if data.CertPEM.IsUnknown() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeytosEzcaSslCertResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data KeytosEzcaSslCertResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	data.sourceTag = sourceTag(ctx, req.ProviderMeta, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.loadPrivateKey(&resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Resources created before the request was saved in DER format
+	if data.CertRequestDER.IsNull() {
+		if der, err := csr(data.CertRequestPEM.ValueString()); err == nil {
+			data.CertRequestDER = types.StringValue(base64.StdEncoding.EncodeToString(der))
+		}
+	}
+
+	revoked := r.read(ctx, &data.KeytosEzcaSslLeafCertResourceModel, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if revoked {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	tflog.Trace(ctx, "read and updated the resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeytosEzcaSslCertResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var newm, oldm KeytosEzcaSslCertResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newm)...)
+	newm.sourceTag = sourceTag(ctx, req.ProviderMeta, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &oldm)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	newm.loadPrivateKey(&resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.update(ctx, &newm.KeytosEzcaSslLeafCertResourceModel, &oldm.KeytosEzcaSslLeafCertResourceModel, &resp.Diagnostics)
+	// A certificate may have been issued even when other errors occurred,
+	// in which case it must still be saved
+	if newm.CertPEM.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &newm)...)
+}
+
+func (r *KeytosEzcaSslCertResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data KeytosEzcaSslCertResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.delete(ctx, &data.KeytosEzcaSslLeafCertResourceModel, &resp.Diagnostics)
+}
+
+// loadPrivateKey parses the generated private key of the model so that it is
+// included in the PKCS #12 archive.
+func (m *KeytosEzcaSslCertResourceModel) loadPrivateKey(diags *diag.Diagnostics) {
+	if m.PrivateKeyPEM.IsNull() || m.PrivateKeyPEM.IsUnknown() {
+		return
+	}
+	key, err := parsePrivateKeyPEM(m.PrivateKeyPEM.ValueString())
+	if err != nil {
+		diags.AddError("Invalid Internal State", fmt.Sprintf("Invalid private key PEM: %v", err))
+		return
+	}
+	m.privateKey = key
+}
+
+func generatePrivateKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case keyAlgorithmRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case keyAlgorithmRSA3072:
+		return rsa.GenerateKey(rand.Reader, 3072)
+	case keyAlgorithmRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case keyAlgorithmECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case keyAlgorithmECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case keyAlgorithmECDSAP521:
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case keyAlgorithmEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %q", algorithm)
+	}
+}
+
+// publicKeyAlgorithm returns the algorithm of the public key of the
+// certificate, in the format of the key_algorithm attribute.
+func publicKeyAlgorithm(cert *x509.Certificate) string {
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA-%d", pub.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA-" + strings.ReplaceAll(pub.Curve.Params().Name, "-", "")
+	case ed25519.PublicKey:
+		return keyAlgorithmEd25519
+	default:
+		return cert.PublicKeyAlgorithm.String()
+	}
+}
+
+// parsePrivateKeyPEM parses the first private key PEM block of s, in PKCS #8,
+// PKCS #1 or SEC 1 format.
+func parsePrivateKeyPEM(s string) (crypto.Signer, error) {
+	b, _ := pem.Decode([]byte(s))
+	if b == nil {
+		return nil, errors.New("no valid PEM block found as private key")
+	}
+	var key any
+	var err error
+	switch b.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(b.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(b.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(b.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", b.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
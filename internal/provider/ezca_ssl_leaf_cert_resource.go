@@ -6,22 +6,39 @@ package provider
 
 import (
 	"context"
+	"crypto"
 	"crypto/sha1"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -30,6 +47,10 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &KeytosEzcaSslLeafCertResource{}
+var _ resource.ResourceWithModifyPlan = &KeytosEzcaSslLeafCertResource{}
+var _ resource.ResourceWithValidateConfig = &KeytosEzcaSslLeafCertResource{}
+var _ resource.ResourceWithConfigValidators = &KeytosEzcaSslLeafCertResource{}
+var _ resource.ResourceWithUpgradeState = &KeytosEzcaSslLeafCertResource{}
 
 func NewKeytosEzcaSslLeafCertResource() resource.Resource {
 	return &KeytosEzcaSslLeafCertResource{}
@@ -37,29 +58,86 @@ func NewKeytosEzcaSslLeafCertResource() resource.Resource {
 
 // KeytosEzcaSslLeafCert defines the resource implementation.
 type KeytosEzcaSslLeafCertResource struct {
-	client *ezca.Client
+	client             *ezcaClient
+	credential         azcore.TokenCredential
+	fipsMode           bool
+	refreshFailureMode string
+	revocation         *revocationChecker
 }
 
 // KeytosEzcaSslLeafCertModel describes the resource data model.
 type KeytosEzcaSslLeafCertResourceModel struct {
-	AuthorityID    types.String `tfsdk:"authority_id"`
-	TemplateID     types.String `tfsdk:"template_id"`
-	CertRequestPEM types.String `tfsdk:"cert_request_pem"`
-	ValidityPeriod types.String `tfsdk:"validity_period"`
-
-	KeyUsages                         types.List   `tfsdk:"key_usages"`
-	ExtendedKeyUsages                 types.List   `tfsdk:"extended_key_usages"`
-	OverwriteSubjectName              types.Object `tfsdk:"overwrite_subject_name"`
-	OverwriteSubjectNameStr           types.String `tfsdk:"overwrite_subject_name_str"`
-	AdditionalSubjectAlternativeNames types.Object `tfsdk:"additional_subject_alternative_names"`
-	EarlyRenewalPeriod                types.String `tfsdk:"early_renewal_period"`
+	AuthorityID       types.String  `tfsdk:"authority_id"`
+	TemplateID        types.String  `tfsdk:"template_id"`
+	CertRequestPEM    types.String  `tfsdk:"cert_request_pem"`
+	CertRequestDER    types.String  `tfsdk:"cert_request_der_base64"`
+	ValidityPeriod    durationValue `tfsdk:"validity_period"`
+	RequestedNotAfter types.String  `tfsdk:"requested_not_after"`
+
+	KeyUsages                         types.Set              `tfsdk:"key_usages"`
+	ExtendedKeyUsages                 types.Set              `tfsdk:"extended_key_usages"`
+	ForwardRequestUsages              types.Bool             `tfsdk:"forward_request_usages"`
+	OverwriteSubjectName              types.Object           `tfsdk:"overwrite_subject_name"`
+	OverwriteSubjectNameStr           distinguishedNameValue `tfsdk:"overwrite_subject_name_str"`
+	SubjectValidationProfile          types.String           `tfsdk:"subject_validation_profile"`
+	AdditionalSubjectAlternativeNames types.Object           `tfsdk:"additional_subject_alternative_names"`
+	EarlyRenewalPeriod                durationValue          `tfsdk:"early_renewal_period"`
+	RenewBeforePercent                types.Int64            `tfsdk:"renew_before_percent"`
+	RotationSchedule                  durationValue          `tfsdk:"rotation_schedule"`
+	RenewalTriggers                   types.Map              `tfsdk:"renewal_triggers"`
+	PEMLineLength                     types.Int64            `tfsdk:"pem_line_length"`
+	PEMExplanatoryText                types.Bool             `tfsdk:"pem_explanatory_text"`
+	PKCS12Password                    types.String           `tfsdk:"pkcs12_password"`
+	Retry                             types.Object           `tfsdk:"retry"`
+	RequestTimeout                    types.String           `tfsdk:"request_timeout"`
+	Timeouts                          timeouts.Value         `tfsdk:"timeouts"`
+	SkipRevokeOnDestroy               types.Bool             `tfsdk:"skip_revoke_on_destroy"`
+	AllowRevoke                       types.Bool             `tfsdk:"allow_revoke"`
+	RevokePreviousOnRenewal           types.Bool             `tfsdk:"revoke_previous_on_renewal"`
+	DetectRevocation                  types.Bool             `tfsdk:"detect_revocation"`
+	WaitForOCSP                       types.Bool             `tfsdk:"wait_for_ocsp"`
+	WaitForOCSPTimeout                durationValue          `tfsdk:"wait_for_ocsp_timeout"`
+	RequireSCT                        types.Bool             `tfsdk:"require_sct"`
+	SourceTag                         types.String           `tfsdk:"source_tag"`
 
 	CertPEM           types.String `tfsdk:"cert_pem"`
+	CertDERBase64     types.String `tfsdk:"cert_der_base64"`
 	CertThumbprintHex types.String `tfsdk:"cert_thumbprint_hex"`
 	CertSerialNumber  types.String `tfsdk:"cert_serial_number"`
 	ReadyForRenewal   types.Bool   `tfsdk:"ready_for_renewal"`
 	ValidityNotBefore types.String `tfsdk:"validity_not_before"`
 	ValidityNotAfter  types.String `tfsdk:"validity_not_after"`
+
+	PublicKeyAlgorithm types.String `tfsdk:"public_key_algorithm"`
+	SignatureAlgorithm types.String `tfsdk:"signature_algorithm"`
+	SCTListBase64      types.String `tfsdk:"sct_list_base64"`
+
+	IssuedDNSNames       types.List `tfsdk:"issued_dns_names"`
+	IssuedIPAddresses    types.List `tfsdk:"issued_ip_addresses"`
+	IssuedURIs           types.List `tfsdk:"issued_uris"`
+	IssuedEmailAddresses types.List `tfsdk:"issued_email_addresses"`
+
+	OCSPServers            types.List `tfsdk:"ocsp_servers"`
+	IssuingCertificateURLs types.List `tfsdk:"issuing_certificate_urls"`
+	CRLDistributionPoints  types.List `tfsdk:"crl_distribution_points"`
+
+	IssuerSubject          types.String `tfsdk:"issuer_subject"`
+	IssuerThumbprintHex    types.String `tfsdk:"issuer_thumbprint_hex"`
+	AuthorityKeyIdentifier types.String `tfsdk:"authority_key_identifier"`
+
+	CAChainPEM              types.List   `tfsdk:"ca_chain_pem"`
+	CertFullChainPEM        types.String `tfsdk:"cert_full_chain_pem"`
+	TruststoreDebianCRT     types.String `tfsdk:"truststore_debian_crt"`
+	TruststoreRHELAnchorPEM types.String `tfsdk:"truststore_rhel_anchor_pem"`
+	TruststoreMacOSPEM      types.String `tfsdk:"truststore_macos_pem"`
+
+	PKCS12Base64 types.String `tfsdk:"pkcs12_base64"`
+
+	// sourceTag identifies the requests made for the resource in EZCA.
+	sourceTag string
+	// privateKey is the private key of the certificate when it is known to
+	// the provider, to include in the PKCS #12 archive.
+	privateKey crypto.Signer
 }
 
 type SubjectNameAttributeModel struct {
@@ -80,140 +158,477 @@ type SubjectAlternativeNamesAttributeModel struct {
 	URIs           types.List `tfsdk:"uris"`
 }
 
+// keyUsages are the key usages EZCA accepts.
+var keyUsages = []string{
+	string(ezca.KeyUsageDigitalSignature),
+	string(ezca.KeyUsageKeyEncipherment),
+	string(ezca.KeyUsageDataEncipherment),
+	string(ezca.KeyUsageKeyAgreement),
+	string(ezca.KeyUsageNonRepudiation),
+}
+
+// extendedKeyUsages are the extended key usages EZCA accepts, in dotted
+// notation.
+var extendedKeyUsages = []string{
+	string(ezca.ExtKeyUsageAny),
+	string(ezca.ExtKeyUsageServerAuth),
+	string(ezca.ExtKeyUsageClientAuth),
+	string(ezca.ExtKeyUsageCodeSigning),
+	string(ezca.ExtKeyUsageEmailProtection),
+	string(ezca.ExtKeyUsageIPSECEndSystem),
+	string(ezca.ExtKeyUsageIPSECTunnel),
+	string(ezca.ExtKeyUsageIPSECUser),
+	string(ezca.ExtKeyUsageTimeStamping),
+	string(ezca.ExtKeyUsageOCSPSigning),
+	string(ezca.ExtKeyUsageMicrosoftServerGatedCrypto),
+	string(ezca.ExtKeyUsageNetscapeServerGatedCrypto),
+	string(ezca.ExtKeyUsageMicrosoftCommercialCodeSigning),
+	string(ezca.ExtKeyUsageMicrosoftKernelCodeSigning),
+}
+
+var subjectNameAttributeTypes = map[string]attr.Type{
+	"common_name":         types.StringType,
+	"country":             types.ListType{ElemType: types.StringType},
+	"organization":        types.ListType{ElemType: types.StringType},
+	"organizational_unit": types.ListType{ElemType: types.StringType},
+	"locality":            types.ListType{ElemType: types.StringType},
+	"province":            types.ListType{ElemType: types.StringType},
+	"street_address":      types.ListType{ElemType: types.StringType},
+	"postal_code":         types.ListType{ElemType: types.StringType},
+}
+
+var subjectAlternativeNamesAttributeTypes = map[string]attr.Type{
+	"dns_names":       types.ListType{ElemType: types.StringType},
+	"email_addresses": types.ListType{ElemType: types.StringType},
+	"ip_addresses":    types.ListType{ElemType: types.StringType},
+	"uris":            types.ListType{ElemType: types.StringType},
+}
+
 func (r *KeytosEzcaSslLeafCertResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_ezca_ssl_leaf_cert"
 }
 
 func (r *KeytosEzcaSslLeafCertResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := sslCertSchemaAttributes()
+	attributes["cert_request_pem"] = schema.StringAttribute{
+		MarkdownDescription: "Certificate request data in PEM format. Exactly one of `cert_request_pem` or `cert_request_der_base64` must be set, the PEM encoding of the latter is then computed.",
+		Optional:            true,
+		Computed:            true,
+		Validators: []validator.String{
+			stringvalidator.ExactlyOneOf(path.MatchRoot("cert_request_der_base64")),
+		},
+	}
+	attributes["cert_request_der_base64"] = schema.StringAttribute{
+		MarkdownDescription: "Certificate request data in DER format, base64 encoded, for enrollment tooling that outputs raw PKCS #10 requests. Alternative to `cert_request_pem`.",
+		Optional:            true,
+	}
+
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Crates a leaf certificate that is issued by an EZCA SSL authority. If the resource is deleted prior to expiration, it will be revoked.",
+		Version:             sslCertSchemaVersion,
+		Blocks:              sslCertSchemaBlocks(ctx),
 
-		Attributes: map[string]schema.Attribute{
-			"authority_id": schema.StringAttribute{
-				MarkdownDescription: "EZCA SSL authority identifier",
-				Required:            true,
+		Attributes: attributes,
+	}
+}
+
+// sslCertSchemaAttributes returns the schema attributes shared by the
+// resources issuing certificates from an EZCA SSL authority, everything but
+// how the certificate request is provided.
+func sslCertSchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"authority_id": schema.StringAttribute{
+			MarkdownDescription: "EZCA SSL authority identifier",
+			Required:            true,
+			Validators: []validator.String{
+				uuidValidator{},
 			},
-			"template_id": schema.StringAttribute{
-				MarkdownDescription: "EZCA authority SSL template identifier",
-				Required:            true,
+		},
+		"template_id": schema.StringAttribute{
+			MarkdownDescription: "EZCA authority SSL template identifier",
+			Required:            true,
+			Validators: []validator.String{
+				uuidValidator{},
 			},
-			"cert_request_pem": schema.StringAttribute{
-				MarkdownDescription: "Certificate request data in PEM format",
-				Required:            true,
+		},
+		"validity_period": schema.StringAttribute{
+			CustomType:          durationType{},
+			MarkdownDescription: "Validity period that the certificate will remain valid for. " + durationUnitsDescription + " Exactly one of `validity_period` or `requested_not_after` must be set.",
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.ExactlyOneOf(path.MatchRoot("requested_not_after")),
 			},
-			"validity_period": schema.StringAttribute{
-				MarkdownDescription: "Validity period that the certificate will remain valid for",
-				Required:            true,
+		},
+		"requested_not_after": schema.StringAttribute{
+			MarkdownDescription: "Time until which the certificate will remain valid as an RFC3339 timestamp, for certificates that must expire on a fixed date regardless of when they are issued. The validity period is computed when signing, so renewed certificates expire at the same time: move it forward before the certificate is ready for renewal.",
+			Optional:            true,
+			Validators: []validator.String{
+				timestampValidator{},
 			},
+		},
 
-			"key_usages": schema.ListAttribute{
-				ElementType:         types.StringType,
-				MarkdownDescription: "List of key usages. Defaults to key encipherment and digital signature.",
-				Optional:            true,
-				Computed:            true,
+		"key_usages": schema.SetAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "Set of key usages, among " + quotedList(keyUsages) + ". Defaults to key encipherment and digital signature.",
+			Optional:            true,
+			Computed:            true,
+			Validators: []validator.Set{
+				setvalidator.ValueStringsAre(stringvalidator.OneOf(keyUsages...)),
 			},
-			"extended_key_usages": schema.ListAttribute{
-				ElementType:         types.StringType,
-				MarkdownDescription: "List of extended key usages. Defaults to server authentication and client authentication.",
-				Optional:            true,
-				Computed:            true,
+		},
+		"extended_key_usages": schema.SetAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "Set of extended key usages as object identifiers in dotted notation, among " + quotedList(extendedKeyUsages) + ". Defaults to server authentication and client authentication.",
+			Optional:            true,
+			Computed:            true,
+			Validators: []validator.Set{
+				setvalidator.ValueStringsAre(stringvalidator.OneOf(extendedKeyUsages...)),
 			},
-			"overwrite_subject_name": schema.SingleNestedAttribute{
-				Attributes: map[string]schema.Attribute{
-					"common_name": schema.StringAttribute{Optional: true},
-					"country": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
-					},
-					"organization": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
-					},
-					"organizational_unit": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
-					},
-					"locality": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
-					},
-					"province": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
-					},
-					"street_address": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
-					},
-					"postal_code": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
-					},
+		},
+		"forward_request_usages": schema.BoolAttribute{
+			MarkdownDescription: "When true, the key usages and extended key usages requested in the extensionRequest attribute of the certificate request are forwarded to EZCA when `key_usages` or `extended_key_usages` is not set, instead of the defaults, so that appliances encoding their required usages in the request work as is. Only usages EZCA accepts are forwarded. Other attributes of the request, such as the challenge password, cannot be passed to EZCA and are ignored. Defaults to true, or to false for resources created before this attribute was added so that their certificates keep their usages.",
+			Optional:            true,
+			Computed:            true,
+			PlanModifiers: []planmodifier.Bool{
+				priorStateBoolDefault{value: true, prior: false},
+			},
+		},
+		"overwrite_subject_name": schema.SingleNestedAttribute{
+			Attributes: map[string]schema.Attribute{
+				"common_name": schema.StringAttribute{Optional: true},
+				"country": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"organization": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"organizational_unit": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"locality": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"province": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"street_address": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"postal_code": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
 				},
-				MarkdownDescription: "Set to override the Subject Name of the certificate structurally. Can only define one of `overwrite_subject_name` or `overwrite_subject_name_str`.",
-				Optional:            true,
-				Computed:            true,
 			},
-			"overwrite_subject_name_str": schema.StringAttribute{
-				MarkdownDescription: "Set to override the Subject Name of the certificate as a string. Can only define one of `overwrite_subject_name` or `overwrite_subject_name_str`.",
-				Optional:            true,
-				Computed:            true,
+			MarkdownDescription: "Set to override the Subject Name of the certificate structurally. Omit `common_name` for a certificate whose identity lives entirely in its subject alternative names, as the CA/Browser Forum baseline requirements prefer, which requires subject alternative names in the certificate request or `additional_subject_alternative_names`. Can only define one of `overwrite_subject_name` or `overwrite_subject_name_str`.",
+			Optional:            true,
+			Computed:            true,
+		},
+		"overwrite_subject_name_str": schema.StringAttribute{
+			CustomType:          distinguishedNameType{},
+			MarkdownDescription: "Set to override the Subject Name of the certificate as an RFC 4514 distinguished name string, such as `CN=www.example.com,O=Example,C=US`. The name is validated during plan and sent to EZCA in canonical form, so that changing the spacing, escaping or case of attribute types, or the order of the attributes of a multi-valued name, does not issue a new certificate. Can only define one of `overwrite_subject_name` or `overwrite_subject_name_str`.",
+			Optional:            true,
+			Computed:            true,
+		},
+		"subject_validation_profile": schema.StringAttribute{
+			MarkdownDescription: "Validation of `overwrite_subject_name` during plan. One of `none`, `basic` (countries must be ISO 3166-1 alpha-2 codes) or `strict` (`basic`, plus a single country, an organization when an organizational unit is set, and non-empty values within the RFC 5280 length limits). Defaults to `none`.",
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.OneOf(subjectProfileNone, subjectProfileBasic, subjectProfileStrict),
 			},
-			"additional_subject_alternative_names": schema.SingleNestedAttribute{
-				Attributes: map[string]schema.Attribute{
-					"dns_names": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
-					},
-					"email_addresses": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
-					},
-					"ip_addresses": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
-					},
-					"uris": schema.ListAttribute{
-						ElementType: types.StringType,
-						Optional:    true,
+		},
+		"additional_subject_alternative_names": schema.SingleNestedAttribute{
+			Attributes: map[string]schema.Attribute{
+				"dns_names": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"email_addresses": schema.ListAttribute{
+					ElementType:         types.StringType,
+					MarkdownDescription: "Email addresses. Internationalized domains are converted to their ASCII form and domains are lower cased when issuing the certificate.",
+					Optional:            true,
+					Validators: []validator.List{
+						listvalidator.ValueStringsAre(emailAddressValidator{}),
 					},
 				},
-				MarkdownDescription: "Additional subject alternative names to add to the certificate",
-				Optional:            true,
-				Computed:            true,
-			},
-			"early_renewal_period": schema.StringAttribute{
-				MarkdownDescription: "Resource will consider the leaf certificate ready for renewal early by the duration defined here. This can be used to update the resource-managed certificate when close to expiring when it is applied during the early renewal period.",
-				Optional:            true,
-				Computed:            true,
-			},
-
-			"cert_pem": schema.StringAttribute{
-				MarkdownDescription: "Certificate data in PEM format.",
-				Computed:            true,
-			},
-			"cert_thumbprint_hex": schema.StringAttribute{
-				MarkdownDescription: "Certificate thumbprint. This is a SHA-1 sum of the raw certificate contents.",
-				Computed:            true,
+				"ip_addresses": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"uris": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
 			},
-			"cert_serial_number": schema.StringAttribute{
-				MarkdownDescription: "Certificate serial number. The unique identifier for this resource.",
-				Computed:            true,
+			MarkdownDescription: "Additional subject alternative names to add to the certificate",
+			Optional:            true,
+			Computed:            true,
+		},
+		"early_renewal_period": schema.StringAttribute{
+			CustomType:          durationType{},
+			MarkdownDescription: "Resource will consider the leaf certificate ready for renewal early by the duration defined here. This can be used to update the resource-managed certificate when close to expiring when it is applied during the early renewal period. Accepts the same duration units as `validity_period`.",
+			Optional:            true,
+			Computed:            true,
+		},
+		"rotation_schedule": schema.StringAttribute{
+			CustomType:          durationType{},
+			MarkdownDescription: "Resource will consider the leaf certificate ready for renewal once it has been valid for the duration defined here, regardless of its expiration, to rotate certificates more often than their validity period, such as `30d` for monthly rotation of certificates valid for `1y`. Combines with `early_renewal_period` or `renew_before_percent`, whichever comes first. Accepts the same duration units as `validity_period`.",
+			Optional:            true,
+		},
+		"renew_before_percent": schema.Int64Attribute{
+			MarkdownDescription: "Resource will consider the leaf certificate ready for renewal when this percentage of its lifetime remains, such as `30` to renew a 90 day certificate 27 days before it expires. Unlike `early_renewal_period`, it scales with the validity period, which suits modules managing certificates of mixed validity periods. Conflicts with `early_renewal_period`.",
+			Optional:            true,
+			Validators: []validator.Int64{
+				int64validator.Between(1, 99),
+				int64validator.ConflictsWith(path.MatchRoot("early_renewal_period")),
 			},
-			"ready_for_renewal": schema.BoolAttribute{
-				MarkdownDescription: "True when the certificate is expired or when in the early renewal period.",
-				Computed:            true,
+		},
+		"renewal_triggers": schema.MapAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "Arbitrary map of values that, when changed, issue a new certificate. Use it to tie certificate rotation to external events such as key rotations or image builds without changing the certificate request.",
+			Optional:            true,
+		},
+		"pem_line_length": schema.Int64Attribute{
+			MarkdownDescription: "Number of base64 characters per line in `cert_pem`. Defaults to 64 as required by RFC 7468.",
+			Optional:            true,
+			Computed:            true,
+			Default:             int64default.StaticInt64(defaultPEMLineLength),
+			Validators: []validator.Int64{
+				int64validator.Between(16, 128),
 			},
-			"validity_not_before": schema.StringAttribute{
-				MarkdownDescription: "Time after which the certificate is valid as an RFC3339 timestamp. Validity start time stamp.",
-				Computed:            true,
+		},
+		"pem_explanatory_text": schema.BoolAttribute{
+			MarkdownDescription: "When true, `cert_pem` is preceded by `subject=` and `issuer=` explanatory text lines, as produced by OpenSSL. Defaults to false.",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		},
+		"retry": schema.SingleNestedAttribute{
+			Attributes: map[string]schema.Attribute{
+				"max_attempts": schema.Int64Attribute{
+					MarkdownDescription: "Number of times a request is attempted. Defaults to the provider setting.",
+					Optional:            true,
+					Validators: []validator.Int64{
+						int64validator.AtLeast(1),
+					},
+				},
+				"interval": schema.StringAttribute{
+					MarkdownDescription: "Time to wait after the first attempt, as a Go duration string. Defaults to the provider setting.",
+					Optional:            true,
+				},
+				"backoff_multiplier": schema.Float64Attribute{
+					MarkdownDescription: "Factor the time to wait grows by after every attempt. Defaults to the provider setting.",
+					Optional:            true,
+					Validators: []validator.Float64{
+						float64validator.AtLeast(1),
+					},
+				},
+				"max_interval": schema.StringAttribute{
+					MarkdownDescription: "Maximum time to wait between attempts, as a Go duration string. Defaults to the provider setting.",
+					Optional:            true,
+				},
 			},
-			"validity_not_after": schema.StringAttribute{
-				MarkdownDescription: "Time prior which the certificate is valid as an RFC3339 timestamp. Expiration time stamp.",
-				Computed:            true,
+			MarkdownDescription: "Overrides the provider `retry` settings for the requests of this resource, for example to retry more on an unreliable network.",
+			Optional:            true,
+		},
+		"request_timeout": schema.StringAttribute{
+			MarkdownDescription: "Overrides the provider `request_timeout` for the requests of this resource.",
+			Optional:            true,
+		},
+		"source_tag": schema.StringAttribute{
+			MarkdownDescription: fmt.Sprintf("Source tag recorded by EZCA for the certificates issued by the resource, such as a pipeline, workspace or team name, to report on issuance. Changing it applies to the next certificate issued and does not issue a new one. Defaults to `%s`, followed by the module set in the `provider_meta` block if any.", defaultSourceTag),
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.LengthAtLeast(1),
 			},
 		},
+		"skip_revoke_on_destroy": schema.BoolAttribute{
+			MarkdownDescription: "When true, destroying the resource, including to replace it, only removes it from the Terraform state and leaves the certificate valid, for example to hand it over to another system. Must be applied before the destroy to take effect. Defaults to false.",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		},
+		"allow_revoke": schema.BoolAttribute{
+			MarkdownDescription: "When false, destroying the resource, including to replace it, fails instead of revoking the certificate, protecting production certificates from a `terraform destroy` in the wrong workspace. Set it to true, or set `skip_revoke_on_destroy`, and apply before destroying the resource. Renewals are not affected, see `revoke_previous_on_renewal`. Defaults to true.",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(true),
+		},
+		"revoke_previous_on_renewal": schema.BoolAttribute{
+			MarkdownDescription: "When false, the previous certificate is left valid until it expires when the certificate is renewed or a new one is issued in place, such as when `additional_subject_alternative_names` grows, so that it keeps working while the new certificate propagates, for example through load balancers. Certificates replaced by destroying the resource are revoked unless `skip_revoke_on_destroy` is set. When true, the previous certificate is only revoked once the new one is issued. Defaults to true.",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(true),
+		},
+		"wait_for_ocsp": schema.BoolAttribute{
+			MarkdownDescription: "When true, issuing a certificate waits until the OCSP responder of the authority reports its status, so that resources depending on it, such as appliances validating certificates with OCSP, do not race the authority. A warning is raised when the responder does not know the certificate within `wait_for_ocsp_timeout`. Defaults to false.",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		},
+		"wait_for_ocsp_timeout": schema.StringAttribute{
+			CustomType:          durationType{},
+			MarkdownDescription: fmt.Sprintf("How long to wait for the OCSP responder when `wait_for_ocsp` is set. Accepts the same duration units as `validity_period`. Defaults to `%s`.", defaultOCSPWaitTimeout),
+			Optional:            true,
+		},
+		"require_sct": schema.BoolAttribute{
+			MarkdownDescription: "When true, the apply fails when the issued certificate has no embedded certificate transparency signed certificate timestamps, for authorities expected to log their certificates. The certificate is still saved, a newly created resource being tainted so that the next apply replaces it. Defaults to false.",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		},
+		"detect_revocation": schema.BoolAttribute{
+			MarkdownDescription: "When true, refreshing the resource checks the certificate with the OCSP responder of the authority, and removes the resource from the state when the certificate was revoked outside of Terraform so that the next apply issues a new one. Checks of a refresh are batched per authority and cached for the run. Defaults to false.",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		},
+		"pkcs12_password": schema.StringAttribute{
+			MarkdownDescription: "Password protecting `pkcs12_base64`. Set it, possibly to an empty string, to get the certificate as a PKCS #12 archive. Changing it re-encodes the archive without issuing a new certificate.",
+			Optional:            true,
+			Sensitive:           true,
+		},
+
+		"cert_pem": schema.StringAttribute{
+			MarkdownDescription: "Certificate data in PEM format.",
+			Computed:            true,
+		},
+		"cert_der_base64": schema.StringAttribute{
+			MarkdownDescription: "Certificate data in DER format, base64 encoded, for systems that require DER rather than PEM. It can be written to a file as is with the `content_base64` argument of `local_file`.",
+			Computed:            true,
+		},
+		"cert_thumbprint_hex": schema.StringAttribute{
+			MarkdownDescription: "Certificate thumbprint. This is a SHA-1 sum of the raw certificate contents.",
+			Computed:            true,
+		},
+		"cert_serial_number": schema.StringAttribute{
+			MarkdownDescription: "Certificate serial number. The unique identifier for this resource.",
+			Computed:            true,
+		},
+		"ready_for_renewal": schema.BoolAttribute{
+			MarkdownDescription: "True when the certificate is expired or when in the early renewal period. When true, the next plan renews the certificate in place.",
+			Computed:            true,
+		},
+		"validity_not_before": schema.StringAttribute{
+			MarkdownDescription: "Time after which the certificate is valid as an RFC3339 timestamp. Validity start time stamp.",
+			Computed:            true,
+		},
+		"validity_not_after": schema.StringAttribute{
+			MarkdownDescription: "Time prior which the certificate is valid as an RFC3339 timestamp. Expiration time stamp.",
+			Computed:            true,
+		},
+		"public_key_algorithm": schema.StringAttribute{
+			MarkdownDescription: "Algorithm of the public key of the issued certificate, such as `RSA-2048`, `ECDSA-P256` or `Ed25519`, in the format of the `key_algorithm` attribute of `keytos_ezca_ssl_cert`.",
+			Computed:            true,
+		},
+		"signature_algorithm": schema.StringAttribute{
+			MarkdownDescription: "Algorithm the authority signed the issued certificate with, such as `SHA256-RSA` or `ECDSA-SHA384`. It is set by the authority and cannot be requested.",
+			Computed:            true,
+		},
+		"sct_list_base64": schema.StringAttribute{
+			MarkdownDescription: "Certificate transparency signed certificate timestamp list embedded in the issued certificate, TLS encoded as defined by RFC 6962 and base64 encoded. Null when the certificate has none.",
+			Computed:            true,
+		},
+		"issued_dns_names": schema.ListAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "DNS names of the subject alternative names of the issued certificate, from the certificate request, `additional_subject_alternative_names` and the authority template.",
+			Computed:            true,
+		},
+		"issued_ip_addresses": schema.ListAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "IP addresses of the subject alternative names of the issued certificate.",
+			Computed:            true,
+		},
+		"issued_uris": schema.ListAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "URIs of the subject alternative names of the issued certificate.",
+			Computed:            true,
+		},
+		"issued_email_addresses": schema.ListAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "Email addresses of the subject alternative names of the issued certificate.",
+			Computed:            true,
+		},
+		"ocsp_servers": schema.ListAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "OCSP responder URLs from the authority information access extension of the issued certificate, for example to allow them through proxies and firewalls.",
+			Computed:            true,
+		},
+		"issuing_certificate_urls": schema.ListAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "Issuing authority certificate URLs from the authority information access extension of the issued certificate.",
+			Computed:            true,
+		},
+		"crl_distribution_points": schema.ListAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "CRL distribution point URLs of the issued certificate.",
+			Computed:            true,
+		},
+		"issuer_subject": schema.StringAttribute{
+			MarkdownDescription: "Distinguished name of the issuer of the issued certificate, in RFC 4514 format.",
+			Computed:            true,
+		},
+		"issuer_thumbprint_hex": schema.StringAttribute{
+			MarkdownDescription: "SHA-1 thumbprint of the issuing authority certificate, the first certificate of `ca_chain_pem`, in hexadecimal, for example to pin it. Null when EZCA returned no authority chain.",
+			Computed:            true,
+		},
+		"authority_key_identifier": schema.StringAttribute{
+			MarkdownDescription: "Authority key identifier of the issued certificate, identifying the key of the issuing authority, in hexadecimal. Null when the certificate has none.",
+			Computed:            true,
+		},
+
+		"pkcs12_base64": schema.StringAttribute{
+			MarkdownDescription: "Certificate, authority chain and, when known to the provider, private key as a base64 encoded PKCS #12 (PFX) archive protected by `pkcs12_password`, for Windows, IIS and Java consumers. Null when `pkcs12_password` is not set.",
+			Computed:            true,
+			Sensitive:           true,
+		},
+		"ca_chain_pem": schema.ListAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "Authority chain of the certificate returned by EZCA, as a list of certificates in PEM format from the issuing authority up to the root.",
+			Computed:            true,
+		},
+		"cert_full_chain_pem": schema.StringAttribute{
+			MarkdownDescription: "Certificate followed by the intermediate authorities of `ca_chain_pem` in PEM format, as expected by nginx, HAProxy and Kubernetes TLS secrets. The self-signed root is left out. It is formatted like `cert_pem`.",
+			Computed:            true,
+		},
+		"truststore_debian_crt": schema.StringAttribute{
+			MarkdownDescription: "Authority chain of the certificate as a Debian `ca-certificates` drop-in. Write it to a `.crt` file under `/usr/local/share/ca-certificates/` and run `update-ca-certificates`.",
+			Computed:            true,
+		},
+		"truststore_rhel_anchor_pem": schema.StringAttribute{
+			MarkdownDescription: "Authority chain of the certificate as a RHEL anchor file. Write it to a `.pem` file under `/etc/pki/ca-trust/source/anchors/` and run `update-ca-trust`.",
+			Computed:            true,
+		},
+		"truststore_macos_pem": schema.StringAttribute{
+			MarkdownDescription: "Authority chain of the certificate as bare PEM blocks that can be imported on macOS with `security add-trusted-cert` or `security import`.",
+			Computed:            true,
+		},
+	}
+}
+
+// defaultOperationTimeout is how long creating, updating or deleting a
+// certificate may take, retries and OCSP waits included, unless overridden in
+// the timeouts block.
+const defaultOperationTimeout = 20 * time.Minute
+
+// sslCertSchemaBlocks returns the blocks shared by the SSL certificate
+// resources.
+func sslCertSchemaBlocks(ctx context.Context) map[string]schema.Block {
+	return map[string]schema.Block{
+		"timeouts": timeouts.Block(ctx, timeouts.Opts{
+			Create:            true,
+			Update:            true,
+			Delete:            true,
+			CreateDescription: fmt.Sprintf("How long signing the certificate request may take, including retries and `wait_for_ocsp`. Defaults to `%s`.", defaultOperationTimeout),
+			UpdateDescription: fmt.Sprintf("How long renewing the certificate and revoking the previous one may take. Defaults to `%s`.", defaultOperationTimeout),
+			DeleteDescription: fmt.Sprintf("How long revoking the certificate may take. Defaults to `%s`.", defaultOperationTimeout),
+		}),
 	}
 }
 
@@ -222,7 +637,7 @@ func (r *KeytosEzcaSslLeafCertResource) Configure(ctx context.Context, req resou
 		return
 	}
 
-	client, ok := req.ProviderData.(*ezca.Client)
+	data, ok := req.ProviderData.(*KeytosData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
@@ -231,138 +646,262 @@ func (r *KeytosEzcaSslLeafCertResource) Configure(ctx context.Context, req resou
 		return
 	}
 
-	r.client = client
+	r.client = data.Client
+	r.credential = data.Credential
+	r.fipsMode = data.FIPSMode
+	r.refreshFailureMode = data.RefreshFailureMode
+	r.revocation = data.Revocation
 }
 
 func (r *KeytosEzcaSslLeafCertResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data KeytosEzcaSslLeafCertResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	data.sourceTag = sourceTag(ctx, req.ProviderMeta, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	c, err := r.sslAuthorityClient(ctx, &data)
+	r.create(ctx, &data, &resp.Diagnostics)
+	// A certificate may have been issued even when other errors occurred,
+	// in which case it must still be saved, the resource being tainted
+	if data.CertPEM.IsUnknown() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// create signs the certificate request of the planned model and saves the
+// issued certificate into it.
+func (r *KeytosEzcaSslLeafCertResource) create(ctx context.Context, data *KeytosEzcaSslLeafCertResourceModel, diags *diag.Diagnostics) {
+	timeout, d := data.Timeouts.Create(ctx, defaultOperationTimeout)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c, err := r.sslAuthorityClient(ctx, data)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %v", err))
+		diags.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %s", ezcaErrorDetail(err)))
 		return
 	}
+	r.sign(ctx, c, data, diags)
+}
 
+// sign signs the certificate request of the model with signer, saving the
+// issued certificate into the model.
+func (r *KeytosEzcaSslLeafCertResource) sign(ctx context.Context, c certificateSigner, data *KeytosEzcaSslLeafCertResourceModel, diags *diag.Diagnostics) {
 	csr, err := csr(data.CertRequestPEM.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Invalid Certificate Request PEM", fmt.Sprintf("Error raised when getting CSR PEM: %v", err))
+		diags.AddError("Invalid Certificate Request PEM", fmt.Sprintf("Error raised when getting CSR PEM: %v", err))
 		return
 	}
+	if r.fipsMode {
+		checkFIPSCertificateRequest(data.CertRequestPEM.ValueString(), diags)
+		if diags.HasError() {
+			return
+		}
+	}
 
-	signOptions := buildSignOptions(ctx, &data, &resp.Diagnostics)
-	if resp.Diagnostics.HasError() {
+	signOptions := buildSignOptions(ctx, data, diags)
+	if diags.HasError() {
 		return
 	}
 	tflog.Trace(ctx, "validated inputs")
 
 	erp := time.Duration(0)
 	if !data.EarlyRenewalPeriod.IsUnknown() {
-		erp, err = time.ParseDuration(data.EarlyRenewalPeriod.ValueString())
+		erp, err = data.EarlyRenewalPeriod.Duration()
 		if err != nil {
-			resp.Diagnostics.AddError("Invalid Validity Period", fmt.Sprintf("Invalid duration string: %v", err))
+			diags.AddError("Invalid Validity Period", fmt.Sprintf("Invalid duration string: %v", err))
 			return
 		}
 	} else {
-		data.EarlyRenewalPeriod = types.StringNull()
+		data.EarlyRenewalPeriod = durationNull()
 	}
 
 	if erp > signOptions.Duration {
-		resp.Diagnostics.AddError("Invalid Early Renewal Period", "Early renewal period greater than certificate duration")
+		diags.AddError("Invalid Early Renewal Period", "Early renewal period greater than certificate duration")
 		return
 	}
 
 	certs, err := c.Sign(ctx, csr, signOptions)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Signing", fmt.Sprintf("Error signing CSR: %v", err))
+		diags.AddError("Error Signing", fmt.Sprintf("Error signing CSR: %s", ezcaErrorDetail(err)))
 		return
 	}
-	saveCertificate(&data, certs[0], erp)
+	saveCertificate(data, certs, erp, diags)
+	verifyIssuedCertificate(csr, signOptions, certs[0], diags)
+	checkSCTs(data, diags)
+	r.waitForOCSP(ctx, data, certs, diags)
 	tflog.Trace(ctx, "signed certificate request")
-
-	tflog.Trace(ctx, "created a resource")
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *KeytosEzcaSslLeafCertResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data KeytosEzcaSslLeafCertResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	data.sourceTag = sourceTag(ctx, req.ProviderMeta, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	c, err := r.sslAuthorityClient(ctx, &data)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %v", err))
+	revoked := r.read(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	if revoked {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	tflog.Trace(ctx, "read and updated the resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
 
+// read refreshes the model from the state, flagging the certificate when it
+// is ready for renewal. The renewal itself is planned by ModifyPlan and
+// happens in Update. It reports whether the certificate was revoked outside
+// of Terraform, in which case the resource must be removed from the state.
+func (r *KeytosEzcaSslLeafCertResource) read(ctx context.Context, data *KeytosEzcaSslLeafCertResourceModel, diags *diag.Diagnostics) bool {
 	notAfterStr := data.ValidityNotAfter.ValueString()
 	notAfter, err := time.Parse(time.RFC3339, notAfterStr)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Invalid Internal State",
 			fmt.Sprintf("Invalid certificate expiration time stamp: %q: %v", notAfterStr, err),
 		)
-		return
+		return false
 	}
 
 	erp := time.Duration(0)
 	if data.EarlyRenewalPeriod.IsUnknown() {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Invalid Internal State",
 			"Invalid certificate early renewal period: unknown",
 		)
-		return
+		return false
 	}
 	if !data.EarlyRenewalPeriod.IsNull() {
-		erp, err = time.ParseDuration(data.EarlyRenewalPeriod.ValueString())
+		erp, err = data.EarlyRenewalPeriod.Duration()
 		if err != nil {
-			resp.Diagnostics.AddError("Invalid Validity Period", fmt.Sprintf("Invalid duration string: %v", err))
+			diags.AddError("Invalid Validity Period", fmt.Sprintf("Invalid duration string: %v", err))
+			return false
 		}
 	}
-
-	renewal := readyForRenewal(notAfter, erp)
-
-	if renewal {
-		csr, err := csr(data.CertRequestPEM.ValueString())
+	// Lifetime-relative renewal needs the start of the validity, which models
+	// built by other resources, such as the certificate pair, do not have
+	if !data.ValidityNotBefore.IsNull() && !data.ValidityNotBefore.IsUnknown() {
+		notBeforeStr := data.ValidityNotBefore.ValueString()
+		notBefore, err := time.Parse(time.RFC3339, notBeforeStr)
 		if err != nil {
-			resp.Diagnostics.AddError("Invalid Certificate Request PEM", fmt.Sprintf("Error raised when getting CSR PEM: %v", err))
-			return
+			diags.AddError(
+				"Invalid Internal State",
+				fmt.Sprintf("Invalid certificate start time stamp: %q: %v", notBeforeStr, err),
+			)
+			return false
 		}
-		signOptions := buildSignOptions(ctx, &data, &resp.Diagnostics)
-		if resp.Diagnostics.HasError() {
-			return
+		erp = renewalPeriod(erp, data.RenewBeforePercent, data.RotationSchedule, notBefore, notAfter)
+	}
+	data.ReadyForRenewal = types.BoolValue(readyForRenewal(notAfter, erp))
+	// Resources created before the DER encoding, issued names, algorithms,
+	// authority URLs, issuer and full chain were saved
+	if data.CertDERBase64.IsNull() || data.IssuedDNSNames.IsNull() || data.PublicKeyAlgorithm.IsNull() || data.SignatureAlgorithm.IsNull() || data.OCSPServers.IsNull() || data.SCTListBase64.IsNull() || data.IssuerSubject.IsNull() || data.CertFullChainPEM.IsNull() {
+		if cert, err := parseCertificatePEM(data.CertPEM.ValueString()); err == nil {
+			data.CertDERBase64 = types.StringValue(base64.StdEncoding.EncodeToString(cert.Raw))
+			saveSCTList(data, cert)
+			data.PublicKeyAlgorithm = types.StringValue(publicKeyAlgorithm(cert))
+			data.SignatureAlgorithm = types.StringValue(cert.SignatureAlgorithm.String())
+			saveIssuedNames(data, cert)
+			saveAuthorityURLs(data, cert)
+			var chainPEM []string
+			diags.Append(data.CAChainPEM.ElementsAs(ctx, &chainPEM, false)...)
+			if chain, err := parseCertificatesPEM(strings.Join(chainPEM, "")); err == nil {
+				saveIssuer(data, cert, chain)
+				data.CertFullChainPEM = types.StringValue(encodeFullChainPEM(cert, chain, int(data.PEMLineLength.ValueInt64()), data.PEMExplanatoryText.ValueBool()))
+			}
 		}
-		tflog.Trace(ctx, "fetched existing CSR and sign options")
+	}
 
-		certs, err := c.Sign(ctx, csr, signOptions)
-		if err != nil {
-			resp.Diagnostics.AddError("Error Renewing Certificate", fmt.Sprintf("Error signing CSR: %v", err))
-			return
+	_, err = r.sslAuthorityClient(ctx, data)
+	if err != nil {
+		if r.keepStateOnRefreshFailure(err, diags) {
+			return false
 		}
-		saveCertificate(&data, certs[0], erp)
-		tflog.Trace(ctx, "renewed certificate")
-	} else {
-		data.ReadyForRenewal = types.BoolValue(renewal)
+		diags.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %s", ezcaErrorDetail(err)))
+		return false
 	}
 
-	tflog.Trace(ctx, "read and updated the resource")
+	if !data.DetectRevocation.ValueBool() || r.revocation == nil {
+		return false
+	}
+	return r.revoked(ctx, data, diags)
+}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+// revoked reports whether the certificate is revoked according to the OCSP
+// responder of its authority, warning when its status cannot be checked.
+func (r *KeytosEzcaSslLeafCertResource) revoked(ctx context.Context, data *KeytosEzcaSslLeafCertResourceModel, diags *diag.Diagnostics) bool {
+	cert, err := parseCertificatePEM(data.CertPEM.ValueString())
+	if err != nil {
+		diags.AddError("Invalid Internal State", fmt.Sprintf("Invalid certificate PEM: %v", err))
+		return false
+	}
+	chain := listStringValues(data.CAChainPEM)
+	if len(chain) == 0 {
+		tflog.Debug(ctx, "skipping revocation check of a certificate without authority chain")
+		return false
+	}
+	issuer, err := parseCertificatePEM(chain[0].ValueString())
+	if err != nil {
+		diags.AddError("Invalid Internal State", fmt.Sprintf("Invalid authority certificate PEM: %v", err))
+		return false
+	}
+
+	revoked, revokedAt, err := r.revocation.check(ctx, cert, issuer)
+	if errors.Is(err, errNoOCSPResponder) {
+		tflog.Debug(ctx, "skipping revocation check of a certificate without OCSP responder")
+		return false
+	}
+	if err != nil {
+		diags.AddWarning(
+			"Revocation Check Failed",
+			fmt.Sprintf("Could not check whether the certificate with serial number %s is revoked: %v", cert.SerialNumber, err),
+		)
+		return false
+	}
+	if revoked {
+		diags.AddWarning(
+			"Certificate Revoked",
+			fmt.Sprintf("The certificate with serial number %s was revoked outside of Terraform at %s, removing it from the state so that it is issued again.", cert.SerialNumber, revokedAt.Format(time.RFC3339)),
+		)
+	}
+	return revoked
+}
+
+// keepStateOnRefreshFailure reports whether the resource must be kept as is
+// after err occurred refreshing it, warning about it when so.
+func (r *KeytosEzcaSslLeafCertResource) keepStateOnRefreshFailure(err error, diags *diag.Diagnostics) bool {
+	if r.refreshFailureMode != refreshFailureModeWarnAndKeepState || !isEndpointUnavailable(err) {
+		return false
+	}
+	diags.AddWarning(
+		"EZCA Unavailable During Refresh",
+		fmt.Sprintf("EZCA could not be reached to refresh the certificate, keeping it as stored in the state: %v", err),
+	)
+	return true
 }
 
 func (r *KeytosEzcaSslLeafCertResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var newm, oldm KeytosEzcaSslLeafCertResourceModel
-	var err error
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &newm)...)
+	newm.sourceTag = sourceTag(ctx, req.ProviderMeta, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -371,113 +910,395 @@ func (r *KeytosEzcaSslLeafCertResource) Update(ctx context.Context, req resource
 		return
 	}
 
+	r.update(ctx, &newm, &oldm, &resp.Diagnostics)
+	// A certificate may have been issued even when other errors occurred,
+	// in which case it must still be saved
+	if newm.CertPEM.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &newm)...)
+}
+
+// update applies the planned model over the prior one, issuing a new
+// certificate when the request changed or the certificate is ready for
+// renewal.
+func (r *KeytosEzcaSslLeafCertResource) update(ctx context.Context, newm, oldm *KeytosEzcaSslLeafCertResourceModel, diags *diag.Diagnostics) {
+	timeout, d := newm.Timeouts.Update(ctx, defaultOperationTimeout)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var err error
+
 	csr, err := csr(newm.CertRequestPEM.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Invalid Certificate Request PEM", fmt.Sprintf("Error raised when getting CSR PEM: %v", err))
+		diags.AddError("Invalid Certificate Request PEM", fmt.Sprintf("Error raised when getting CSR PEM: %v", err))
 		return
 	}
+	if r.fipsMode {
+		checkFIPSCertificateRequest(newm.CertRequestPEM.ValueString(), diags)
+		if diags.HasError() {
+			return
+		}
+	}
 
-	signOptions := buildSignOptions(ctx, &newm, &resp.Diagnostics)
-	if resp.Diagnostics.HasError() {
+	signOptions := buildSignOptions(ctx, newm, diags)
+	if diags.HasError() {
 		return
 	}
 
 	erp := time.Duration(0)
 	if !newm.EarlyRenewalPeriod.IsUnknown() {
-		erp, err = time.ParseDuration(newm.EarlyRenewalPeriod.ValueString())
+		erp, err = newm.EarlyRenewalPeriod.Duration()
 		if err != nil {
-			resp.Diagnostics.AddError("Invalid Validity Period", fmt.Sprintf("Invalid duration string: %v", err))
+			diags.AddError("Invalid Validity Period", fmt.Sprintf("Invalid duration string: %v", err))
 			return
 		}
 	} else {
-		newm.EarlyRenewalPeriod = types.StringNull()
+		newm.EarlyRenewalPeriod = durationNull()
 	}
 
 	if erp > signOptions.Duration {
-		resp.Diagnostics.AddError("Invalid Early Renewal Period", "Early renewal period greater than certificate duration")
+		diags.AddError("Invalid Early Renewal Period", "Early renewal period greater than certificate duration")
 		return
 	}
 
-	if requireNewCertificate(newm, oldm) {
-		c, err := r.sslAuthorityClient(ctx, &oldm)
-		if err != nil {
-			resp.Diagnostics.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %v", err))
-			return
-		}
-		thumbHex := oldm.CertThumbprintHex.ValueString()
-		thumb, err := hex.DecodeString(thumbHex)
-		if err != nil {
-			resp.Diagnostics.AddError("Invalid Certificate Thumbprint", fmt.Sprintf("Error retrieving certificate thumbprint: thumbprint %q: %v", thumbHex, err))
-			return
-		}
-		err = c.RevokeWithThumbprint(ctx, [20]byte(thumb))
-		if err != nil {
-			resp.Diagnostics.AddError("Error Revoking Certificate", fmt.Sprintf("Encountered an error when trying to revoke the old certificate: %v", err))
-		}
-
-		c, err = r.sslAuthorityClient(ctx, &newm)
+	if requireNewCertificate(*newm, *oldm) {
+		c, err := r.sslAuthorityClient(ctx, newm)
 		if err != nil {
-			resp.Diagnostics.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %v", err))
+			diags.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %s", ezcaErrorDetail(err)))
 			return
 		}
 
-		certs, err := c.Sign(ctx, csr, signOptions)
-		if err != nil {
-			resp.Diagnostics.AddError("Error Signing", fmt.Sprintf("Error signing CSR: %v", err))
+		// The previous certificate may have been issued by another
+		// authority
+		certs := reissue(ctx, newm, oldm, c, func() (certificateRevoker, error) {
+			return r.sslAuthorityClient(ctx, oldm)
+		}, csr, signOptions, erp, "Error Signing", diags)
+		if certs == nil {
 			return
 		}
-		saveCertificate(&newm, certs[0], erp)
+		r.waitForOCSP(ctx, newm, certs, diags)
 
 		tflog.Trace(ctx, "updated the resource with new certificate")
-		resp.Diagnostics.Append(resp.State.Set(ctx, &newm)...)
 	} else {
 		notAfterStr := oldm.ValidityNotAfter.ValueString()
 		notAfter, err := time.Parse(time.RFC3339, notAfterStr)
 		if err != nil {
-			resp.Diagnostics.AddError(
+			diags.AddError(
 				"Invalid Internal State",
 				fmt.Sprintf("Invalid certificate expiration time stamp: %q: %v", notAfterStr, err),
 			)
 			return
 		}
+		notBeforeStr := oldm.ValidityNotBefore.ValueString()
+		notBefore, err := time.Parse(time.RFC3339, notBeforeStr)
+		if err != nil {
+			diags.AddError(
+				"Invalid Internal State",
+				fmt.Sprintf("Invalid certificate start time stamp: %q: %v", notBeforeStr, err),
+			)
+			return
+		}
 
-		if readyForRenewal(notAfter, erp) {
-			c, err := r.sslAuthorityClient(ctx, &newm)
+		if readyForRenewal(notAfter, renewalPeriod(erp, newm.RenewBeforePercent, newm.RotationSchedule, notBefore, notAfter)) {
+			c, err := r.sslAuthorityClient(ctx, newm)
 			if err != nil {
-				resp.Diagnostics.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %v", err))
+				diags.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %s", ezcaErrorDetail(err)))
 				return
 			}
 
-			thumbHex := oldm.CertThumbprintHex.ValueString()
-			thumb, err := hex.DecodeString(thumbHex)
-			if err != nil {
-				resp.Diagnostics.AddError("Invalid Certificate Thumbprint", fmt.Sprintf("Error retrieving certificate thumbprint: thumbprint %q: %v", thumbHex, err))
+			certs := reissue(ctx, newm, oldm, c, func() (certificateRevoker, error) {
+				return c, nil
+			}, csr, signOptions, erp, "Error Renewing Certificate", diags)
+			if certs == nil {
 				return
 			}
-
-			err = c.RevokeWithThumbprint(ctx, [20]byte(thumb))
-			if err != nil {
-				resp.Diagnostics.AddError("Error Revoking Certificate", fmt.Sprintf("Encountered an error when trying to revoke the certificate: %v", err))
-			}
-
-			certs, err := c.Sign(ctx, csr, signOptions)
+			r.waitForOCSP(ctx, newm, certs, diags)
+			tflog.Trace(ctx, "renewed certificate")
+		} else {
+			cert, err := parseCertificatePEM(oldm.CertPEM.ValueString())
 			if err != nil {
-				resp.Diagnostics.AddError("Error Renewing Certificate", fmt.Sprintf("Error signing CSR: %v", err))
+				diags.AddError("Invalid Internal State", fmt.Sprintf("Invalid certificate PEM: %v", err))
 				return
 			}
-			saveCertificate(&newm, certs[0], erp)
-			tflog.Trace(ctx, "renewed certificate")
-		} else {
-			newm.CertPEM = types.StringValue(oldm.CertPEM.ValueString())
+			newm.CertPEM = types.StringValue(encodeCertificatePEM(cert, int(newm.PEMLineLength.ValueInt64()), newm.PEMExplanatoryText.ValueBool()))
+			newm.CertDERBase64 = types.StringValue(base64.StdEncoding.EncodeToString(cert.Raw))
+			newm.PublicKeyAlgorithm = types.StringValue(publicKeyAlgorithm(cert))
+			newm.SignatureAlgorithm = types.StringValue(cert.SignatureAlgorithm.String())
+			saveSCTList(newm, cert)
+			saveIssuedNames(newm, cert)
+			saveAuthorityURLs(newm, cert)
 			newm.CertThumbprintHex = types.StringValue(oldm.CertThumbprintHex.ValueString())
 			newm.CertSerialNumber = types.StringValue(oldm.CertSerialNumber.ValueString())
 			newm.ReadyForRenewal = types.BoolValue(false)
 			newm.ValidityNotBefore = types.StringValue(oldm.ValidityNotBefore.ValueString())
 			newm.ValidityNotAfter = types.StringValue(oldm.ValidityNotAfter.ValueString())
+			newm.IssuerSubject = oldm.IssuerSubject
+			newm.IssuerThumbprintHex = oldm.IssuerThumbprintHex
+			newm.AuthorityKeyIdentifier = oldm.AuthorityKeyIdentifier
+			newm.CAChainPEM = oldm.CAChainPEM
+			newm.TruststoreDebianCRT = oldm.TruststoreDebianCRT
+			newm.TruststoreRHELAnchorPEM = oldm.TruststoreRHELAnchorPEM
+			newm.TruststoreMacOSPEM = oldm.TruststoreMacOSPEM
+			var chainPEM []string
+			diags.Append(oldm.CAChainPEM.ElementsAs(ctx, &chainPEM, false)...)
+			chain, err := parseCertificatesPEM(strings.Join(chainPEM, ""))
+			if err != nil {
+				diags.AddError("Invalid Internal State", fmt.Sprintf("Invalid authority chain PEM: %v", err))
+				return
+			}
+			// The PEM format may have changed
+			newm.CertFullChainPEM = types.StringValue(encodeFullChainPEM(cert, chain, int(newm.PEMLineLength.ValueInt64()), newm.PEMExplanatoryText.ValueBool()))
+			// Archives are encoded with a random salt, only encode a new one
+			// when the password changed
+			if newm.PKCS12Password.Equal(oldm.PKCS12Password) && newm.PKCS12Password.IsNull() == oldm.PKCS12Base64.IsNull() {
+				newm.PKCS12Base64 = oldm.PKCS12Base64
+			} else {
+				savePKCS12(newm, cert, chain, diags)
+			}
 		}
 
 		tflog.Trace(ctx, "updated the resource")
-		resp.Diagnostics.Append(resp.State.Set(ctx, &newm)...)
+	}
+}
+
+// certificateSigner signs certificate requests, as sslAuthorityClient does.
+type certificateSigner interface {
+	Sign(ctx context.Context, csr []byte, opts *ezca.SignOptions) ([]*x509.Certificate, error)
+}
+
+// certificateRevoker revokes certificates, as sslAuthorityClient does.
+type certificateRevoker interface {
+	RevokeWithThumbprint(ctx context.Context, thumbprint [sha1.Size]byte) error
+}
+
+// reissue signs the certificate request with signer, saving the new
+// certificate into newm, and only once it is issued revokes the certificate
+// of oldm when revoke_previous_on_renewal is set, so that a failure to sign
+// never leaves the resource with a revoked certificate and no replacement.
+// The revoker is only created when revoking. It returns the issued
+// certificates, or nil when signing failed.
+func reissue(ctx context.Context, newm, oldm *KeytosEzcaSslLeafCertResourceModel, signer certificateSigner, revoker func() (certificateRevoker, error), csr []byte, signOptions *ezca.SignOptions, erp time.Duration, summary string, diags *diag.Diagnostics) []*x509.Certificate {
+	certs, err := signer.Sign(ctx, csr, signOptions)
+	if err != nil {
+		diags.AddError(summary, fmt.Sprintf("Error signing CSR: %s", ezcaErrorDetail(err)))
+		return nil
+	}
+	saveCertificate(newm, certs, erp, diags)
+	verifyIssuedCertificate(csr, signOptions, certs[0], diags)
+	checkSCTs(newm, diags)
+
+	if !newm.revokePrevious() {
+		tflog.Debug(ctx, "keeping the previous certificate valid", map[string]any{"serial_number": oldm.CertSerialNumber.ValueString()})
+		return certs
+	}
+	thumb, err := revocationThumbprint(oldm)
+	if err != nil {
+		diags.AddError("Invalid Certificate Thumbprint", fmt.Sprintf("Error retrieving certificate thumbprint: %v", err))
+		return certs
+	}
+	c, err := revoker()
+	if err != nil {
+		diags.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %s", ezcaErrorDetail(err)))
+		return certs
+	}
+	if err := c.RevokeWithThumbprint(ctx, thumb); err != nil {
+		diags.AddError("Error Revoking Certificate", fmt.Sprintf("The new certificate was issued, but an error was encountered when trying to revoke the previous certificate with serial number %s: %s", oldm.CertSerialNumber.ValueString(), ezcaErrorDetail(err)))
+	}
+	return certs
+}
+
+func (r *KeytosEzcaSslLeafCertResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("overwrite_subject_name"),
+			path.MatchRoot("overwrite_subject_name_str"),
+		),
+	}
+}
+
+func (r *KeytosEzcaSslLeafCertResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var profile types.String
+	var subjectName types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("subject_validation_profile"), &profile)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("overwrite_subject_name"), &subjectName)...)
+	if resp.Diagnostics.HasError() || profile.IsUnknown() {
+		return
+	}
+	checkSubjectNameProfile(ctx, profile.ValueString(), subjectName, &resp.Diagnostics)
+	checkSubjectNameIdentity(ctx, req.Config, subjectName, &resp.Diagnostics)
+}
+
+// checkSubjectNameIdentity validates that a certificate whose subject name
+// override has no common name, so that its identity lives entirely in the
+// subject alternative names, has subject alternative names.
+func checkSubjectNameIdentity(ctx context.Context, config tfsdk.Config, subjectName types.Object, diags *diag.Diagnostics) {
+	if subjectName.IsNull() || subjectName.IsUnknown() {
+		return
+	}
+	commonName, ok := subjectName.Attributes()["common_name"].(types.String)
+	if !ok || commonName.IsUnknown() || commonName.ValueString() != "" {
+		return
+	}
+
+	var sans types.Object
+	var csrPEM, csrDERBase64 types.String
+	diags.Append(config.GetAttribute(ctx, path.Root("additional_subject_alternative_names"), &sans)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("cert_request_pem"), &csrPEM)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("cert_request_der_base64"), &csrDERBase64)...)
+	if diags.HasError() || !sans.IsNull() || csrPEM.IsUnknown() || csrDERBase64.IsUnknown() {
+		return
+	}
+	// The certificate request is generated without subject alternative
+	// names when not configured
+	if !csrPEM.IsNull() || !csrDERBase64.IsNull() {
+		var der []byte
+		var err error
+		if !csrPEM.IsNull() {
+			der, err = csr(csrPEM.ValueString())
+		} else {
+			der, err = base64.StdEncoding.DecodeString(csrDERBase64.ValueString())
+		}
+		if err != nil {
+			return
+		}
+		req, err := x509.ParseCertificateRequest(der)
+		if err != nil || len(req.DNSNames)+len(req.EmailAddresses)+len(req.IPAddresses)+len(req.URIs) > 0 {
+			return
+		}
+	}
+	diags.AddAttributeError(
+		path.Root("overwrite_subject_name").AtName("common_name"),
+		"Missing Certificate Identity",
+		"Without a common name, the identity of the certificate lives entirely in its subject alternative names, but neither the certificate request nor additional_subject_alternative_names has any.",
+	)
+}
+
+func (r *KeytosEzcaSslLeafCertResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// Requests in DER format are planned in PEM format, which the rest of the
+	// resource works with
+	var csrDERBase64 types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("cert_request_der_base64"), &csrDERBase64)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !csrDERBase64.IsNull() && !csrDERBase64.IsUnknown() {
+		csrPEM, err := certificateRequestPEM(csrDERBase64.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("cert_request_der_base64"), "Invalid Certificate Request DER", fmt.Sprintf("Error parsing certificate request: %v", err))
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("cert_request_pem"), csrPEM)...)
+	}
+
+	if r.fipsMode {
+		var csrPEM types.String
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("cert_request_pem"), &csrPEM)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Unknown requests are checked again during apply
+		if !csrPEM.IsUnknown() {
+			checkFIPSCertificateRequest(csrPEM.ValueString(), &resp.Diagnostics)
+		}
+	}
+
+	// Nothing to renew on create
+	if req.State.Raw.IsNull() {
+		return
+	}
+	planRenewal(ctx, req, resp)
+}
+
+// certificateAttributes are the computed attributes that change when a new
+// certificate is issued.
+var certificateAttributes = map[string]attr.Value{
+	"cert_pem":                   types.StringUnknown(),
+	"cert_der_base64":            types.StringUnknown(),
+	"cert_thumbprint_hex":        types.StringUnknown(),
+	"cert_serial_number":         types.StringUnknown(),
+	"validity_not_before":        types.StringUnknown(),
+	"validity_not_after":         types.StringUnknown(),
+	"public_key_algorithm":       types.StringUnknown(),
+	"signature_algorithm":        types.StringUnknown(),
+	"sct_list_base64":            types.StringUnknown(),
+	"issued_dns_names":           types.ListUnknown(types.StringType),
+	"issued_ip_addresses":        types.ListUnknown(types.StringType),
+	"issued_uris":                types.ListUnknown(types.StringType),
+	"issued_email_addresses":     types.ListUnknown(types.StringType),
+	"ocsp_servers":               types.ListUnknown(types.StringType),
+	"issuing_certificate_urls":   types.ListUnknown(types.StringType),
+	"crl_distribution_points":    types.ListUnknown(types.StringType),
+	"issuer_subject":             types.StringUnknown(),
+	"issuer_thumbprint_hex":      types.StringUnknown(),
+	"authority_key_identifier":   types.StringUnknown(),
+	"ready_for_renewal":          types.BoolUnknown(),
+	"ca_chain_pem":               types.ListUnknown(types.StringType),
+	"cert_full_chain_pem":        types.StringUnknown(),
+	"truststore_debian_crt":      types.StringUnknown(),
+	"truststore_rhel_anchor_pem": types.StringUnknown(),
+	"truststore_macos_pem":       types.StringUnknown(),
+	"pkcs12_base64":              types.StringUnknown(),
+}
+
+// planRenewal plans the in-place renewal of the certificate when it is ready
+// for renewal, so that it shows in the plan rather than happening during
+// refresh.
+func planRenewal(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var notBeforeStr, notAfterStr types.String
+	var erpStr, rotation durationValue
+	var percent types.Int64
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("validity_not_before"), &notBeforeStr)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("validity_not_after"), &notAfterStr)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("early_renewal_period"), &erpStr)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("renew_before_percent"), &percent)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("rotation_schedule"), &rotation)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if erpStr.IsUnknown() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("early_renewal_period"), &erpStr)...)
+	}
+	if percent.IsUnknown() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("renew_before_percent"), &percent)...)
+	}
+	if rotation.IsUnknown() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("rotation_schedule"), &rotation)...)
+	}
+
+	notBefore, err := time.Parse(time.RFC3339, notBeforeStr.ValueString())
+	if err != nil {
+		// Reported when refreshing or applying
+		return
+	}
+	notAfter, err := time.Parse(time.RFC3339, notAfterStr.ValueString())
+	if err != nil {
+		return
+	}
+	erp := time.Duration(0)
+	if !erpStr.IsNull() && !erpStr.IsUnknown() {
+		erp, err = erpStr.Duration()
+		if err != nil {
+			return
+		}
+	}
+	if !readyForRenewal(notAfter, renewalPeriod(erp, percent, rotation, notBefore, notAfter)) {
+		return
+	}
+
+	tflog.Debug(ctx, "planning certificate renewal", map[string]any{"validity_not_after": notAfterStr.ValueString()})
+	for name, v := range certificateAttributes {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), v)...)
 	}
 }
 
@@ -489,28 +1310,52 @@ func (r *KeytosEzcaSslLeafCertResource) Delete(ctx context.Context, req resource
 		return
 	}
 
-	c, err := r.sslAuthorityClient(ctx, &data)
+	r.delete(ctx, &data, &resp.Diagnostics)
+}
+
+// delete revokes the certificate of the model, unless skipped.
+func (r *KeytosEzcaSslLeafCertResource) delete(ctx context.Context, data *KeytosEzcaSslLeafCertResourceModel, diags *diag.Diagnostics) {
+	if data.SkipRevokeOnDestroy.ValueBool() {
+		tflog.Debug(ctx, "skipping revocation on destroy", map[string]any{"serial_number": data.CertSerialNumber.ValueString()})
+		return
+	}
+	if !data.allowRevoke() {
+		diags.AddError(
+			"Revocation Not Allowed",
+			fmt.Sprintf("Destroying this resource would revoke the certificate with serial number %s, but `allow_revoke` is false. To destroy it, first apply `allow_revoke = true`, or `skip_revoke_on_destroy = true` to leave the certificate valid.", data.CertSerialNumber.ValueString()),
+		)
+		return
+	}
+
+	timeout, d := data.Timeouts.Delete(ctx, defaultOperationTimeout)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c, err := r.sslAuthorityClient(ctx, data)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %v", err))
+		diags.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %s", ezcaErrorDetail(err)))
 		return
 	}
 
-	thumbHex := data.CertThumbprintHex.ValueString()
-	thumb, err := hex.DecodeString(thumbHex)
+	thumb, err := revocationThumbprint(data)
 	if err != nil {
-		resp.Diagnostics.AddError("Invalid Certificate Thumbprint", fmt.Sprintf("Error retrieving certificate thumbprint: thumbprint %q: %v", thumbHex, err))
+		diags.AddError("Invalid Certificate Thumbprint", fmt.Sprintf("Error retrieving certificate thumbprint: %v", err))
 		return
 	}
 
 	tflog.Trace(ctx, "deleted the resource")
 
-	err = c.RevokeWithThumbprint(ctx, [20]byte(thumb))
+	err = c.RevokeWithThumbprint(ctx, thumb)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Revoking Certificate", fmt.Sprintf("Encountered an error when trying to revoke the certificate: %v", err))
+		diags.AddError("Error Revoking Certificate", fmt.Sprintf("Encountered an error when trying to revoke the certificate: %s", ezcaErrorDetail(err)))
 	}
 }
 
-func (r *KeytosEzcaSslLeafCertResource) sslAuthorityClient(ctx context.Context, data *KeytosEzcaSslLeafCertResourceModel) (c *ezca.SSLAuthorityClient, err error) {
+func (r *KeytosEzcaSslLeafCertResource) sslAuthorityClient(ctx context.Context, data *KeytosEzcaSslLeafCertResourceModel) (c *sslAuthorityClient, err error) {
 	authorityId, e := uuid.Parse(data.AuthorityID.ValueString())
 	if e != nil {
 		err = errors.Join(err, fmt.Errorf("expected a valid UUID for Authority ID, got %s: %w", authorityId, e))
@@ -523,7 +1368,12 @@ func (r *KeytosEzcaSslLeafCertResource) sslAuthorityClient(ctx context.Context,
 		return
 	}
 
-	c, e = ezca.NewSSLAuthorityClient(ctx, r.client, authorityId, templateId)
+	policy, e := r.client.policy.override(ctx, data.Retry, data.RequestTimeout)
+	if e != nil {
+		return nil, e
+	}
+
+	c, e = r.client.withPolicy(policy).SSLAuthority(ctx, authorityId, templateId)
 	if e != nil {
 		err = errors.Join(err, fmt.Errorf("error getting SSL Authority client: %w", e))
 	}
@@ -541,17 +1391,54 @@ func csr(s string) ([]byte, error) {
 	return b.Bytes, nil
 }
 
+// certificateRequestPEM returns the PEM encoding of the base64 encoded DER
+// certificate request, after checking that it parses.
+func certificateRequestPEM(derBase64 string) (string, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(derBase64))
+	if err != nil {
+		return "", err
+	}
+	if _, err := x509.ParseCertificateRequest(der); err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})), nil
+}
+
 func buildSignOptions(ctx context.Context, m *KeytosEzcaSslLeafCertResourceModel, diags *diag.Diagnostics) *ezca.SignOptions {
 	var e error
 	var listVals []types.String
-	signOptions := &ezca.SignOptions{SourceTag: "keytos terraform provider"}
+	signOptions := &ezca.SignOptions{SourceTag: m.sourceTag}
+	if !m.SourceTag.IsNull() && !m.SourceTag.IsUnknown() {
+		signOptions.SourceTag = m.SourceTag.ValueString()
+	}
+	if signOptions.SourceTag == "" {
+		signOptions.SourceTag = defaultSourceTag
+	}
 
-	signOptions.Duration, e = time.ParseDuration(m.ValidityPeriod.ValueString())
-	if e != nil {
-		diags.AddError("Invalid Duration String", fmt.Sprintf("Invalid duration string: %v", e))
-		return nil
+	if !m.RequestedNotAfter.IsNull() {
+		notAfter, e := time.Parse(time.RFC3339, m.RequestedNotAfter.ValueString())
+		if e != nil {
+			diags.AddError("Invalid Requested Expiration", fmt.Sprintf("Invalid RFC3339 timestamp: %v", e))
+			return nil
+		}
+		signOptions.Duration = time.Until(notAfter)
+		if signOptions.Duration <= 0 {
+			diags.AddError("Invalid Requested Expiration", fmt.Sprintf("Requested expiration %s is in the past.", m.RequestedNotAfter.ValueString()))
+			return nil
+		}
+	} else {
+		signOptions.Duration, e = m.ValidityPeriod.Duration()
+		if e != nil {
+			diags.AddError("Invalid Duration String", fmt.Sprintf("Invalid duration string: %v", e))
+			return nil
+		}
 	}
 
+	var requestedKUs []ezca.KeyUsage
+	var requestedEKUs []ezca.ExtKeyUsage
+	if m.forwardRequestUsages() {
+		requestedKUs, requestedEKUs = certificateRequestUsages(m.CertRequestPEM.ValueString())
+	}
 	if !m.KeyUsages.IsUnknown() {
 		if m.KeyUsages.ElementType(ctx) != types.StringType {
 			diags.AddError("Invalid Key Usages", "Passed key usages must be strings")
@@ -563,8 +1450,15 @@ func buildSignOptions(ctx context.Context, m *KeytosEzcaSslLeafCertResourceModel
 		for _, v := range listVals {
 			signOptions.KeyUsages = append(signOptions.KeyUsages, ezca.KeyUsage(v.ValueString()))
 		}
+	} else if len(requestedKUs) > 0 {
+		signOptions.KeyUsages = requestedKUs
+		vals := make([]attr.Value, 0, len(requestedKUs))
+		for _, ku := range requestedKUs {
+			vals = append(vals, types.StringValue(string(ku)))
+		}
+		m.KeyUsages, _ = types.SetValue(types.StringType, vals)
 	} else {
-		m.KeyUsages, _ = types.ListValue(types.StringType, []attr.Value{
+		m.KeyUsages, _ = types.SetValue(types.StringType, []attr.Value{
 			types.StringValue(string(ezca.KeyUsageKeyEncipherment)),
 			types.StringValue(string(ezca.KeyUsageDigitalSignature)),
 		})
@@ -580,8 +1474,15 @@ func buildSignOptions(ctx context.Context, m *KeytosEzcaSslLeafCertResourceModel
 		for _, v := range listVals {
 			signOptions.ExtendedKeyUsages = append(signOptions.ExtendedKeyUsages, ezca.ExtKeyUsage(v.ValueString()))
 		}
+	} else if len(requestedEKUs) > 0 {
+		signOptions.ExtendedKeyUsages = requestedEKUs
+		vals := make([]attr.Value, 0, len(requestedEKUs))
+		for _, eku := range requestedEKUs {
+			vals = append(vals, types.StringValue(string(eku)))
+		}
+		m.ExtendedKeyUsages, _ = types.SetValue(types.StringType, vals)
 	} else {
-		m.ExtendedKeyUsages, _ = types.ListValue(types.StringType, []attr.Value{
+		m.ExtendedKeyUsages, _ = types.SetValue(types.StringType, []attr.Value{
 			types.StringValue(string(ezca.ExtKeyUsageServerAuth)),
 			types.StringValue(string(ezca.ExtKeyUsageClientAuth)),
 		})
@@ -594,7 +1495,7 @@ func buildSignOptions(ctx context.Context, m *KeytosEzcaSslLeafCertResourceModel
 			return nil
 		}
 
-		sn := pkix.Name{CommonName: snm.CommonName.String()}
+		sn := pkix.Name{CommonName: snm.CommonName.ValueString()}
 
 		listVals = make([]types.String, 0, len(snm.Country.Elements()))
 		sn.Country = make([]string, 0, len(snm.Country.Elements()))
@@ -647,25 +1548,20 @@ func buildSignOptions(ctx context.Context, m *KeytosEzcaSslLeafCertResourceModel
 
 		signOptions.SubjectName = sn.String()
 	} else {
-		m.OverwriteSubjectName = types.ObjectNull(map[string]attr.Type{
-			"common_name":         types.StringType,
-			"country":             types.ListType{ElemType: types.StringType},
-			"organization":        types.ListType{ElemType: types.StringType},
-			"organizational_unit": types.ListType{ElemType: types.StringType},
-			"locality":            types.ListType{ElemType: types.StringType},
-			"province":            types.ListType{ElemType: types.StringType},
-			"street_address":      types.ListType{ElemType: types.StringType},
-			"postal_code":         types.ListType{ElemType: types.StringType},
-		})
+		m.OverwriteSubjectName = types.ObjectNull(subjectNameAttributeTypes)
 	}
 	if !m.OverwriteSubjectNameStr.IsUnknown() {
 		if signOptions.SubjectName != "" {
 			diags.AddError("Invalid Overwrite Subject Name", "Only one of \"overwrite_subject_name\" or \"overwrite_subject_name_str\" can be defined")
 			return nil
 		}
-		signOptions.SubjectName = m.OverwriteSubjectNameStr.ValueString()
+		signOptions.SubjectName, e = m.OverwriteSubjectNameStr.Canonical()
+		if e != nil {
+			diags.AddError("Invalid Distinguished Name", fmt.Sprintf("Invalid overwrite subject name string: %v", e))
+			return nil
+		}
 	} else {
-		m.OverwriteSubjectNameStr = types.StringNull()
+		m.OverwriteSubjectNameStr = distinguishedNameNull()
 	}
 	if !m.AdditionalSubjectAlternativeNames.IsUnknown() {
 		var sanm SubjectAlternativeNamesAttributeModel
@@ -686,7 +1582,12 @@ func buildSignOptions(ctx context.Context, m *KeytosEzcaSslLeafCertResourceModel
 		signOptions.EmailAddresses = make([]string, 0, len(sanm.EmailAddresses.Elements()))
 		sanm.EmailAddresses.ElementsAs(ctx, &listVals, false)
 		for _, v := range listVals {
-			signOptions.EmailAddresses = append(signOptions.EmailAddresses, v.ValueString())
+			email, err := normalizeEmailAddress(v.ValueString())
+			if err != nil {
+				diags.AddError("Invalid Subject Alternative Name", fmt.Sprintf("Invalid email address %q: %v", v.ValueString(), err))
+			} else {
+				signOptions.EmailAddresses = append(signOptions.EmailAddresses, email)
+			}
 		}
 
 		listVals = make([]types.String, 0, len(sanm.IPAddresses.Elements()))
@@ -713,32 +1614,246 @@ func buildSignOptions(ctx context.Context, m *KeytosEzcaSslLeafCertResourceModel
 			}
 		}
 	} else {
-		m.AdditionalSubjectAlternativeNames = types.ObjectNull(map[string]attr.Type{
-			"dns_names":       types.ListType{ElemType: types.StringType},
-			"email_addresses": types.ListType{ElemType: types.StringType},
-			"ip_addresses":    types.ListType{ElemType: types.StringType},
-			"uris":            types.ListType{ElemType: types.StringType},
-		})
+		m.AdditionalSubjectAlternativeNames = types.ObjectNull(subjectAlternativeNamesAttributeTypes)
 	}
 
 	return signOptions
 }
 
+// quotedList formats values as code spans for descriptions, such as
+// "`a`, `b` or `c`".
+func quotedList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "`" + v + "`"
+	}
+	if len(quoted) < 2 {
+		return strings.Join(quoted, "")
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
 func readyForRenewal(notAfter time.Time, earlyRenewalPeriod time.Duration) bool {
 	return notAfter.Add(-earlyRenewalPeriod).Before(time.Now())
 }
 
-func saveCertificate(m *KeytosEzcaSslLeafCertResourceModel, cert *x509.Certificate, erp time.Duration) {
+// parseEarlyRenewalPeriod parses an early renewal period, 0 when not set.
+func parseEarlyRenewalPeriod(erp durationValue) (time.Duration, error) {
+	if erp.IsNull() || erp.IsUnknown() {
+		return 0, nil
+	}
+	return erp.Duration()
+}
+
+// renewalPeriod returns how early a certificate valid from notBefore to
+// notAfter is ready for renewal: the percentage of its lifetime when
+// renewBeforePercent is set, the early renewal period otherwise, extended to
+// the rest of its lifetime past the rotation schedule when set.
+func renewalPeriod(erp time.Duration, renewBeforePercent types.Int64, rotation durationValue, notBefore, notAfter time.Time) time.Duration {
+	if !renewBeforePercent.IsNull() && !renewBeforePercent.IsUnknown() {
+		erp = notAfter.Sub(notBefore) * time.Duration(renewBeforePercent.ValueInt64()) / 100
+	}
+	if r, err := parseEarlyRenewalPeriod(rotation); err == nil && r > 0 {
+		erp = max(erp, notAfter.Sub(notBefore.Add(r)))
+	}
+	return erp
+}
+
+// saveIssuedNames saves the subject alternative names of the issued
+// certificate into the model.
+func saveIssuedNames(m *KeytosEzcaSslLeafCertResourceModel, cert *x509.Certificate) {
+	ips := make([]string, 0, len(cert.IPAddresses))
+	for _, ip := range cert.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+	uris := make([]string, 0, len(cert.URIs))
+	for _, u := range cert.URIs {
+		uris = append(uris, u.String())
+	}
+	m.IssuedDNSNames = stringList(cert.DNSNames)
+	m.IssuedIPAddresses = stringList(ips)
+	m.IssuedURIs = stringList(uris)
+	m.IssuedEmailAddresses = stringList(cert.EmailAddresses)
+}
+
+// saveSCTList saves the signed certificate timestamp list embedded in the
+// issued certificate into the model, null when it has none.
+func saveSCTList(m *KeytosEzcaSslLeafCertResourceModel, cert *x509.Certificate) {
+	m.SCTListBase64 = types.StringNull()
+	if list := signedCertificateTimestampList(cert); list != nil {
+		m.SCTListBase64 = types.StringValue(base64.StdEncoding.EncodeToString(list))
+	}
+}
+
+// checkSCTs fails when signed certificate timestamps are required but the
+// certificate saved into the model has none.
+func checkSCTs(m *KeytosEzcaSslLeafCertResourceModel, diags *diag.Diagnostics) {
+	if !m.RequireSCT.ValueBool() {
+		return
+	}
+	list, _ := base64.StdEncoding.DecodeString(m.SCTListBase64.ValueString())
+	if signedCertificateTimestampCount(list) == 0 {
+		diags.AddAttributeError(
+			path.Root("require_sct"),
+			"Missing Signed Certificate Timestamps",
+			fmt.Sprintf("The certificate with serial number %s has no embedded certificate transparency signed certificate timestamps, check that the authority logs its certificates.", m.CertSerialNumber.ValueString()),
+		)
+	}
+}
+
+// saveAuthorityURLs saves the OCSP, issuing certificate and CRL URLs of the
+// issued certificate into the model.
+func saveAuthorityURLs(m *KeytosEzcaSslLeafCertResourceModel, cert *x509.Certificate) {
+	m.OCSPServers = stringList(cert.OCSPServer)
+	m.IssuingCertificateURLs = stringList(cert.IssuingCertificateURL)
+	m.CRLDistributionPoints = stringList(cert.CRLDistributionPoints)
+}
+
+// stringList returns the values as a known list, empty rather than null when
+// there are none.
+func stringList(values []string) types.List {
+	elems := make([]attr.Value, 0, len(values))
+	for _, v := range values {
+		elems = append(elems, types.StringValue(v))
+	}
+	return types.ListValueMust(types.StringType, elems)
+}
+
+// saveCertificate saves the certificates returned when signing, the leaf
+// certificate followed by its authority chain, into the model.
+func saveCertificate(m *KeytosEzcaSslLeafCertResourceModel, certs []*x509.Certificate, erp time.Duration, diags *diag.Diagnostics) {
+	cert, chain := certs[0], certs[1:]
 	thumb := sha1.Sum(cert.Raw)
-	m.CertPEM = types.StringValue(string(pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: cert.Raw,
-	})))
+	m.CertPEM = types.StringValue(encodeCertificatePEM(cert, int(m.PEMLineLength.ValueInt64()), m.PEMExplanatoryText.ValueBool()))
+	m.CertDERBase64 = types.StringValue(base64.StdEncoding.EncodeToString(cert.Raw))
 	m.CertThumbprintHex = types.StringValue(hex.EncodeToString(thumb[:]))
 	m.CertSerialNumber = types.StringValue(cert.SerialNumber.String())
 	m.ValidityNotBefore = types.StringValue(cert.NotBefore.Format(time.RFC3339))
 	m.ValidityNotAfter = types.StringValue(cert.NotAfter.Format(time.RFC3339))
-	m.ReadyForRenewal = types.BoolValue(readyForRenewal(cert.NotAfter, erp))
+	m.ReadyForRenewal = types.BoolValue(readyForRenewal(cert.NotAfter, renewalPeriod(erp, m.RenewBeforePercent, m.RotationSchedule, cert.NotBefore, cert.NotAfter)))
+	m.PublicKeyAlgorithm = types.StringValue(publicKeyAlgorithm(cert))
+	m.SignatureAlgorithm = types.StringValue(cert.SignatureAlgorithm.String())
+	saveSCTList(m, cert)
+	saveIssuedNames(m, cert)
+	saveAuthorityURLs(m, cert)
+	saveIssuer(m, cert, chain)
+	chainPEM := make([]attr.Value, 0, len(chain))
+	for _, c := range chain {
+		chainPEM = append(chainPEM, types.StringValue(encodeCertificatePEM(c, defaultPEMLineLength, false)))
+	}
+	m.CAChainPEM = types.ListValueMust(types.StringType, chainPEM)
+	m.CertFullChainPEM = types.StringValue(encodeFullChainPEM(cert, chain, int(m.PEMLineLength.ValueInt64()), m.PEMExplanatoryText.ValueBool()))
+	m.TruststoreDebianCRT = types.StringValue(encodeDebianCACertificates(chain))
+	m.TruststoreRHELAnchorPEM = types.StringValue(encodeRHELAnchor(chain))
+	m.TruststoreMacOSPEM = types.StringValue(encodeMacOSPEM(chain))
+	savePKCS12(m, cert, chain, diags)
+}
+
+// saveIssuer saves the issuer details of the certificate into the model,
+// its issuing authority being the first certificate of the chain.
+func saveIssuer(m *KeytosEzcaSslLeafCertResourceModel, cert *x509.Certificate, chain []*x509.Certificate) {
+	m.IssuerSubject = types.StringValue(cert.Issuer.String())
+	m.IssuerThumbprintHex = types.StringNull()
+	if len(chain) > 0 {
+		thumb := sha1.Sum(chain[0].Raw)
+		m.IssuerThumbprintHex = types.StringValue(hex.EncodeToString(thumb[:]))
+	}
+	m.AuthorityKeyIdentifier = types.StringNull()
+	if len(cert.AuthorityKeyId) > 0 {
+		m.AuthorityKeyIdentifier = types.StringValue(hex.EncodeToString(cert.AuthorityKeyId))
+	}
+}
+
+// savePKCS12 saves the certificate into the model as a PKCS #12 archive when
+// a password is set.
+func savePKCS12(m *KeytosEzcaSslLeafCertResourceModel, cert *x509.Certificate, chain []*x509.Certificate, diags *diag.Diagnostics) {
+	if m.PKCS12Password.IsNull() {
+		m.PKCS12Base64 = types.StringNull()
+		return
+	}
+	pfx, err := encodePKCS12(m.privateKey, cert, chain, m.PKCS12Password.ValueString())
+	if err != nil {
+		// The certificate is issued, so this must not prevent saving it
+		diags.AddWarning("Error Encoding PKCS #12", fmt.Sprintf("Could not encode the certificate as a PKCS #12 archive, leaving pkcs12_base64 empty: %v", err))
+		m.PKCS12Base64 = types.StringNull()
+		return
+	}
+	m.PKCS12Base64 = types.StringValue(base64.StdEncoding.EncodeToString(pfx))
+}
+
+// revocationThumbprint returns the thumbprint to revoke the certificate of
+// the model with. It is computed from cert_pem when it can be parsed, so that
+// an altered cert_thumbprint_hex does not prevent revocation, and read from
+// cert_thumbprint_hex otherwise.
+func revocationThumbprint(m *KeytosEzcaSslLeafCertResourceModel) ([sha1.Size]byte, error) {
+	if cert, err := parseCertificatePEM(m.CertPEM.ValueString()); err == nil {
+		return sha1.Sum(cert.Raw), nil
+	}
+	thumbHex := m.CertThumbprintHex.ValueString()
+	thumb, err := hex.DecodeString(thumbHex)
+	if err != nil {
+		return [sha1.Size]byte{}, fmt.Errorf("thumbprint %q: %w", thumbHex, err)
+	}
+	if len(thumb) != sha1.Size {
+		return [sha1.Size]byte{}, fmt.Errorf("thumbprint %q: expected %d bytes, got %d", thumbHex, sha1.Size, len(thumb))
+	}
+	return [sha1.Size]byte(thumb), nil
+}
+
+// waitForOCSP waits until the OCSP responder of the authority reports the
+// status of the issued certificate, when wait_for_ocsp is set.
+func (r *KeytosEzcaSslLeafCertResource) waitForOCSP(ctx context.Context, m *KeytosEzcaSslLeafCertResourceModel, certs []*x509.Certificate, diags *diag.Diagnostics) {
+	if !m.WaitForOCSP.ValueBool() {
+		return
+	}
+	if len(certs) < 2 {
+		diags.AddWarning("Certificate Availability Not Checked", "The authority did not return the issuer of the certificate, which is required to query its OCSP responder.")
+		return
+	}
+
+	timeout := defaultOCSPWaitTimeout
+	if !m.WaitForOCSPTimeout.IsNull() && !m.WaitForOCSPTimeout.IsUnknown() {
+		var err error
+		timeout, err = m.WaitForOCSPTimeout.Duration()
+		if err != nil {
+			diags.AddError("Invalid Duration String", fmt.Sprintf("Invalid OCSP wait timeout: %v", err))
+			return
+		}
+	}
+	checker := r.revocation
+	if checker == nil {
+		checker = newRevocationChecker()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := checker.waitUntilKnown(ctx, certs[0], certs[1], defaultOCSPWaitInterval)
+	if errors.Is(err, errNoOCSPResponder) {
+		diags.AddWarning("Certificate Availability Not Checked", "The issued certificate has no OCSP responder to wait for.")
+	} else if err != nil {
+		diags.AddWarning(
+			"Certificate Not Yet Available",
+			fmt.Sprintf("The OCSP responder did not report the status of the certificate with serial number %s within %s: %v", certs[0].SerialNumber, timeout, err),
+		)
+	}
+}
+
+// revokePrevious reports whether the certificate being renewed or replaced in
+// place is revoked, the default.
+func (m *KeytosEzcaSslLeafCertResourceModel) revokePrevious() bool {
+	return m.RevokePreviousOnRenewal.IsNull() || m.RevokePreviousOnRenewal.ValueBool()
+}
+
+// forwardRequestUsages reports whether the usages requested in the
+// certificate request are used when not configured, the default.
+func (m *KeytosEzcaSslLeafCertResourceModel) forwardRequestUsages() bool {
+	return m.ForwardRequestUsages.IsNull() || m.ForwardRequestUsages.ValueBool()
+}
+
+// allowRevoke reports whether destroying the resource may revoke the
+// certificate, the default.
+func (m *KeytosEzcaSslLeafCertResourceModel) allowRevoke() bool {
+	return m.AllowRevoke.IsNull() || m.AllowRevoke.ValueBool()
 }
 
 func requireNewCertificate(left, right KeytosEzcaSslLeafCertResourceModel) bool {
@@ -746,9 +1861,11 @@ func requireNewCertificate(left, right KeytosEzcaSslLeafCertResourceModel) bool
 		!left.TemplateID.Equal(right.TemplateID) ||
 		!left.CertRequestPEM.Equal(right.CertRequestPEM) ||
 		!left.ValidityPeriod.Equal(right.ValidityPeriod) ||
+		!left.RequestedNotAfter.Equal(right.RequestedNotAfter) ||
 		!left.KeyUsages.Equal(right.KeyUsages) ||
 		!left.ExtendedKeyUsages.Equal(right.ExtendedKeyUsages) ||
 		!left.OverwriteSubjectName.Equal(right.OverwriteSubjectName) ||
 		!left.OverwriteSubjectNameStr.Equal(right.OverwriteSubjectNameStr) ||
-		!left.AdditionalSubjectAlternativeNames.Equal(right.AdditionalSubjectAlternativeNames)
+		!left.AdditionalSubjectAlternativeNames.Equal(right.AdditionalSubjectAlternativeNames) ||
+		!left.RenewalTriggers.Equal(right.RenewalTriggers)
 }
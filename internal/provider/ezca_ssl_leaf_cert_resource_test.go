@@ -5,13 +5,33 @@
 package provider
 
 import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"math/big"
+	"net"
+	"net/url"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/compare"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 	"github.com/markeytos/ezca-go"
@@ -26,6 +46,8 @@ func TestAccKeytosEzcaSslLeafCert(t *testing.T) {
 	require.NoError(t, err)
 	serialNumberRegexp, err := regexp.Compile(`[0-9]+`)
 	require.NoError(t, err)
+	base64Regexp, err := regexp.Compile(`^[A-Za-z0-9+/]+=*$`)
+	require.NoError(t, err)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { acctest.PreCheck(t) },
@@ -58,7 +80,7 @@ func TestAccKeytosEzcaSslLeafCert(t *testing.T) {
 					statecheck.ExpectKnownValue(
 						"keytos_ezca_ssl_leaf_cert.test",
 						tfjsonpath.New("key_usages"),
-						knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.SetExact([]knownvalue.Check{
 							knownvalue.StringExact(string(ezca.KeyUsageKeyEncipherment)),
 							knownvalue.StringExact(string(ezca.KeyUsageDigitalSignature)),
 						}),
@@ -66,7 +88,7 @@ func TestAccKeytosEzcaSslLeafCert(t *testing.T) {
 					statecheck.ExpectKnownValue(
 						"keytos_ezca_ssl_leaf_cert.test",
 						tfjsonpath.New("extended_key_usages"),
-						knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.SetExact([]knownvalue.Check{
 							knownvalue.StringExact(string(ezca.ExtKeyUsageServerAuth)),
 							knownvalue.StringExact(string(ezca.ExtKeyUsageClientAuth)),
 						}),
@@ -103,6 +125,26 @@ func TestAccKeytosEzcaSslLeafCert(t *testing.T) {
 						tfjsonpath.New("cert_pem"),
 						knownvalue.StringRegexp(certPEMRegexp),
 					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("cert_der_base64"),
+						knownvalue.StringRegexp(base64Regexp),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("signature_algorithm"),
+						knownvalue.StringRegexp(regexp.MustCompile(`SHA(256|384|512)`)),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("issuer_thumbprint_hex"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^[0-9a-f]{40}$`)),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("issued_dns_names"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("test.com")}),
+					),
 					statecheck.ExpectKnownValue(
 						"keytos_ezca_ssl_leaf_cert.test",
 						tfjsonpath.New("cert_thumbprint_hex"),
@@ -128,6 +170,28 @@ func TestAccKeytosEzcaSslLeafCert(t *testing.T) {
 						tfjsonpath.New("validity_not_after"),
 						knownvalue.StringFunc(verifyRFC3339),
 					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("ca_chain_pem"),
+						knownvalue.ListPartial(map[int]knownvalue.Check{
+							0: knownvalue.StringRegexp(certPEMRegexp),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("truststore_debian_crt"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^# .+\n-----BEGIN CERTIFICATE-----\n`)),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("truststore_rhel_anchor_pem"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^subject=.+\nissuer=.+\n-----BEGIN CERTIFICATE-----\n`)),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("truststore_macos_pem"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^-----BEGIN CERTIFICATE-----\n`)),
+					),
 				},
 			},
 			// Update and Read testing
@@ -157,7 +221,7 @@ func TestAccKeytosEzcaSslLeafCert(t *testing.T) {
 					statecheck.ExpectKnownValue(
 						"keytos_ezca_ssl_leaf_cert.test",
 						tfjsonpath.New("key_usages"),
-						knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.SetExact([]knownvalue.Check{
 							knownvalue.StringExact(string(ezca.KeyUsageKeyEncipherment)),
 							knownvalue.StringExact(string(ezca.KeyUsageDigitalSignature)),
 						}),
@@ -165,7 +229,7 @@ func TestAccKeytosEzcaSslLeafCert(t *testing.T) {
 					statecheck.ExpectKnownValue(
 						"keytos_ezca_ssl_leaf_cert.test",
 						tfjsonpath.New("extended_key_usages"),
-						knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.SetExact([]knownvalue.Check{
 							knownvalue.StringExact(string(ezca.ExtKeyUsageServerAuth)),
 							knownvalue.StringExact(string(ezca.ExtKeyUsageClientAuth)),
 						}),
@@ -202,6 +266,11 @@ func TestAccKeytosEzcaSslLeafCert(t *testing.T) {
 						tfjsonpath.New("cert_pem"),
 						knownvalue.StringRegexp(certPEMRegexp),
 					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("cert_der_base64"),
+						knownvalue.StringRegexp(base64Regexp),
+					),
 					statecheck.ExpectKnownValue(
 						"keytos_ezca_ssl_leaf_cert.test",
 						tfjsonpath.New("cert_thumbprint_hex"),
@@ -233,6 +302,397 @@ func TestAccKeytosEzcaSslLeafCert(t *testing.T) {
 	})
 }
 
+func TestAccKeytosEzcaSslLeafCert_renewal(t *testing.T) {
+	serialChanged := statecheck.CompareValue(compare.ValuesDiffer())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// An early renewal period as long as the validity makes the
+			// certificate ready for renewal as soon as it is issued
+			{
+				Config: testAccKeytosEzcaSslLeafCertConfig("24h", "24h"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("ready_for_renewal"),
+						knownvalue.Bool(true),
+					),
+					serialChanged.AddStateValue("keytos_ezca_ssl_leaf_cert.test", tfjsonpath.New("cert_serial_number")),
+				},
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("keytos_ezca_ssl_leaf_cert.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectUnknownValue("keytos_ezca_ssl_leaf_cert.test", tfjsonpath.New("cert_pem")),
+					},
+				},
+				ExpectNonEmptyPlan: true,
+			},
+			// Renewal is applied in place
+			{
+				Config: testAccKeytosEzcaSslLeafCertConfig("24h", "24h"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("keytos_ezca_ssl_leaf_cert.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					serialChanged.AddStateValue("keytos_ezca_ssl_leaf_cert.test", tfjsonpath.New("cert_serial_number")),
+				},
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_renewalTriggers(t *testing.T) {
+	serialChanged := statecheck.CompareValue(compare.ValuesDiffer())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeytosEzcaSslLeafCertPEMFormatConfig(`  renewal_triggers = { image = "v1" }
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					serialChanged.AddStateValue("keytos_ezca_ssl_leaf_cert.test", tfjsonpath.New("cert_serial_number")),
+				},
+			},
+			// Changing a trigger issues a new certificate in place
+			{
+				Config: testAccKeytosEzcaSslLeafCertPEMFormatConfig(`  renewal_triggers = { image = "v2" }
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("keytos_ezca_ssl_leaf_cert.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					serialChanged.AddStateValue("keytos_ezca_ssl_leaf_cert.test", tfjsonpath.New("cert_serial_number")),
+				},
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_subjectValidationProfile(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeytosEzcaSslLeafCertPEMFormatConfig(`  subject_validation_profile = "basic"
+  overwrite_subject_name = {
+    common_name = "test.com"
+    country     = ["USA"]
+  }
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Subject Country`),
+			},
+			{
+				Config: testAccKeytosEzcaSslLeafCertPEMFormatConfig(`  subject_validation_profile = "strict"
+  overwrite_subject_name = {
+    common_name         = "test.com"
+    organizational_unit = ["IT"]
+  }
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Missing Subject Organization`),
+			},
+			{
+				Config: testAccKeytosEzcaSslLeafCertPEMFormatConfig(`  overwrite_subject_name_str = "CN=test.com"
+  overwrite_subject_name = {
+    common_name = "test.com"
+  }
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_durationSyntax(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKeytosEzcaSslLeafCertConfig("1x", "0"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Duration String`),
+			},
+			{
+				Config:             testAccKeytosEzcaSslLeafCertConfig("1w", "1d"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_requestedNotAfter(t *testing.T) {
+	notAfter := time.Now().Add(72 * time.Hour).UTC().Format(time.RFC3339)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(`  validity_period = "24h"`, notAfter),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config:      testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig("", "2025-13-01"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Timestamp`),
+			},
+			{
+				Config: testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig("", notAfter),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("validity_not_after"),
+						knownvalue.StringFunc(func(v string) error {
+							got, err := time.Parse(time.RFC3339, v)
+							if err != nil {
+								return err
+							}
+							want, _ := time.Parse(time.RFC3339, notAfter)
+							if d := got.Sub(want); d < -time.Hour || d > time.Hour {
+								return fmt.Errorf("expected expiration close to %s, got %s", notAfter, v)
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_renewBeforePercent(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig("  renew_before_percent = 30\n  early_renewal_period = \"1h\"", "2099-01-01T00:00:00Z"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config:      testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig("  renew_before_percent = 100", "2099-01-01T00:00:00Z"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value`),
+			},
+			{
+				Config: testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig("  renew_before_percent = 50", time.Now().Add(72*time.Hour).UTC().Format(time.RFC3339)),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("ready_for_renewal"),
+						knownvalue.Bool(false),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_rotationSchedule(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(`  rotation_schedule = "monthly"`, "2099-01-01T00:00:00Z"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Duration String`),
+			},
+			{
+				Config: testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(`  rotation_schedule = "30d"`, time.Now().Add(90*24*time.Hour).UTC().Format(time.RFC3339)),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("ready_for_renewal"),
+						knownvalue.Bool(false),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_timeouts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig("  timeouts {\n    create = \"5 minutes\"\n  }", "2099-01-01T00:00:00Z"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value`),
+			},
+			{
+				Config: testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig("  timeouts {\n    create = \"5m\"\n    delete = \"2m\"\n  }", "2099-01-01T00:00:00Z"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("timeouts").AtMapKey("create"),
+						knownvalue.StringExact("5m"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_keyUsageValues(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(`  extended_key_usages = ["serverauth"]`, "2099-01-01T00:00:00Z"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Invalid Attribute Value Match.*1\.3\.6\.1\.5\.5\.7\.3\.1`),
+			},
+			{
+				Config:      testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(`  key_usages = ["digital signature"]`, "2099-01-01T00:00:00Z"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+			{
+				Config:             testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig("  key_usages = [\"Digital Signature\"]\n  extended_key_usages = [\"1.3.6.1.5.5.7.3.2\", \"1.3.6.1.5.5.7.3.1\"]", "2099-01-01T00:00:00Z"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_subjectNameStr(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(`  overwrite_subject_name_str = "CN=test;O=Example"`, "2099-01-01T00:00:00Z"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Distinguished Name`),
+			},
+			{
+				Config:             testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(`  overwrite_subject_name_str = "cn = test, o = Example"`, "2099-01-01T00:00:00Z"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_withoutCommonName(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(`  overwrite_subject_name = {
+    organization = ["Example"]
+  }`, "2099-01-01T00:00:00Z"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Missing Certificate Identity`),
+			},
+			{
+				Config: testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(`  overwrite_subject_name = {
+    organization = ["Example"]
+  }
+  additional_subject_alternative_names = {
+    dns_names = ["test.com"]
+  }`, "2099-01-01T00:00:00Z"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_pemFormat(t *testing.T) {
+	explanatoryPEMRegexp := regexp.MustCompile(`^subject=.+\nissuer=.+\n-----BEGIN CERTIFICATE-----\n([A-Za-z0-9+/=]{76}\n)+[A-Za-z0-9+/=]{1,76}\n-----END CERTIFICATE-----\n$`)
+	defaultPEMRegexp := regexp.MustCompile(`^-----BEGIN CERTIFICATE-----\n([A-Za-z0-9+/=]{64}\n)+[A-Za-z0-9+/=]{1,64}\n-----END CERTIFICATE-----\n$`)
+	sameSerialNumber := statecheck.CompareValue(compare.ValuesSame())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeytosEzcaSslLeafCertPEMFormatConfig(`
+  pem_line_length = 76
+  pem_explanatory_text = true
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("cert_pem"),
+						knownvalue.StringRegexp(explanatoryPEMRegexp),
+					),
+					sameSerialNumber.AddStateValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("cert_serial_number"),
+					),
+				},
+			},
+			// Formatting changes do not issue a new certificate
+			{
+				Config: testAccKeytosEzcaSslLeafCertPEMFormatConfig(""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("pem_line_length"),
+						knownvalue.Int64Exact(64),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("pem_explanatory_text"),
+						knownvalue.Bool(false),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("cert_pem"),
+						knownvalue.StringRegexp(defaultPEMRegexp),
+					),
+					sameSerialNumber.AddStateValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("cert_serial_number"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccKeytosEzcaSslLeafCert_fipsMode(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKeytosEzcaSslLeafCertFIPSConfig(testCertificateRequestPEM(t, key)),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Non-FIPS Certificate Request`),
+			},
+		},
+	})
+}
+
 const testCSR = `-----BEGIN CERTIFICATE REQUEST-----
 MIIC1zCCAb8CAQAwgZExCzAJBgNVBAYTAlVTMRYwFAYDVQQIDA1NYXNzYWNodXNl
 dHRzMQ8wDQYDVQQHDAZCb3N0b24xDzANBgNVBAoMBktleXRvczEbMBkGA1UECwwS
@@ -268,7 +728,514 @@ resource "keytos_ezca_ssl_leaf_cert" "test" {
 `, test_authority_id, test_template_id, testCSR, validity, earlyRenewal)
 }
 
+func testAccKeytosEzcaSslLeafCertPEMFormatConfig(format string) string {
+	return fmt.Sprintf(`
+resource "keytos_ezca_ssl_leaf_cert" "test" {
+  authority_id = %q
+  template_id = %q
+  cert_request_pem = %q
+  validity_period = "24h"
+%s}
+`, test_authority_id, test_template_id, testCSR, format)
+}
+
+func testAccKeytosEzcaSslLeafCertFIPSConfig(csrPEM string) string {
+	return fmt.Sprintf(`
+provider "keytos" {
+  fips_mode = true
+}
+
+resource "keytos_ezca_ssl_leaf_cert" "test" {
+  authority_id = %q
+  template_id = %q
+  cert_request_pem = %q
+  validity_period = "24h"
+}
+`, test_authority_id, test_template_id, csrPEM)
+}
+
+func testCertificateRequestPEM(t *testing.T, key crypto.Signer) string {
+	t.Helper()
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
 func verifyRFC3339(s string) error {
 	_, err := time.Parse(time.RFC3339, s)
 	return err
 }
+
+func TestKeepStateOnRefreshFailure(t *testing.T) {
+	unreachable := fmt.Errorf("error getting SSL Authority client: %w", &url.Error{Op: "Get", URL: "https://portal.ezca.io", Err: errors.New("connection refused")})
+	denied := errors.New("api error: not authorized")
+
+	tests := []struct {
+		name string
+		mode string
+		err  error
+		keep bool
+	}{
+		{name: "error mode", mode: refreshFailureModeError, err: unreachable, keep: false},
+		{name: "warn mode unreachable", mode: refreshFailureModeWarnAndKeepState, err: unreachable, keep: true},
+		{name: "warn mode api error", mode: refreshFailureModeWarnAndKeepState, err: denied, keep: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &KeytosEzcaSslLeafCertResource{refreshFailureMode: tt.mode}
+			var diags diag.Diagnostics
+			require.Equal(t, tt.keep, r.keepStateOnRefreshFailure(tt.err, &diags))
+			require.Equal(t, tt.keep, diags.WarningsCount() == 1)
+			require.False(t, diags.HasError())
+		})
+	}
+}
+
+func TestSkipRevokeOnDestroy(t *testing.T) {
+	// Without a client, any attempt to revoke would fail
+	r := &KeytosEzcaSslLeafCertResource{}
+	data := &KeytosEzcaSslLeafCertResourceModel{
+		AuthorityID:         types.StringValue(test_authority_id),
+		TemplateID:          types.StringValue(test_template_id),
+		SkipRevokeOnDestroy: types.BoolValue(true),
+	}
+	var diags diag.Diagnostics
+	r.delete(context.Background(), data, &diags)
+	require.False(t, diags.HasError())
+}
+
+func TestRevocationThumbprint(t *testing.T) {
+	_, cert := testSelfSignedCertificate(t, "leaf")
+	certPEM := encodeCertificatePEM(cert, defaultPEMLineLength, false)
+	thumb := sha1.Sum(cert.Raw)
+	thumbHex := hex.EncodeToString(thumb[:])
+
+	tests := []struct {
+		name     string
+		certPEM  types.String
+		thumbHex types.String
+		invalid  bool
+	}{
+		{name: "from certificate", certPEM: types.StringValue(certPEM), thumbHex: types.StringValue(thumbHex)},
+		{name: "altered thumbprint", certPEM: types.StringValue(certPEM), thumbHex: types.StringValue(thumbHex[:10])},
+		{name: "from thumbprint", certPEM: types.StringNull(), thumbHex: types.StringValue(thumbHex)},
+		{name: "truncated thumbprint", certPEM: types.StringNull(), thumbHex: types.StringValue(thumbHex[:10]), invalid: true},
+		{name: "invalid thumbprint", certPEM: types.StringValue("invalid"), thumbHex: types.StringValue("xyz"), invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := revocationThumbprint(&KeytosEzcaSslLeafCertResourceModel{CertPEM: tt.certPEM, CertThumbprintHex: tt.thumbHex})
+			if tt.invalid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, thumb, got)
+		})
+	}
+}
+
+func testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(extra, notAfter string) string {
+	return fmt.Sprintf(`
+resource "keytos_ezca_ssl_leaf_cert" "test" {
+  authority_id = %q
+  template_id = %q
+  cert_request_pem = %q
+  requested_not_after = %q
+%s
+}
+`, test_authority_id, test_template_id, testCSR, notAfter, extra)
+}
+
+func TestAccKeytosEzcaSslLeafCert_certRequestDER(t *testing.T) {
+	der, err := csr(testCSR)
+	require.NoError(t, err)
+	derBase64 := base64.StdEncoding.EncodeToString(der)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKeytosEzcaSslLeafCertRequestedNotAfterConfig(fmt.Sprintf("  cert_request_der_base64 = %q", derBase64), "2099-01-01T00:00:00Z"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config:      testAccKeytosEzcaSslLeafCertDERConfig("bm90IGEgcmVxdWVzdA=="),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Certificate Request DER`),
+			},
+			{
+				Config: testAccKeytosEzcaSslLeafCertDERConfig(derBase64),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert.test",
+						tfjsonpath.New("cert_request_pem"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^-----BEGIN CERTIFICATE REQUEST-----\n`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccKeytosEzcaSslLeafCertDERConfig(derBase64 string) string {
+	return fmt.Sprintf(`
+resource "keytos_ezca_ssl_leaf_cert" "test" {
+  authority_id = %q
+  template_id = %q
+  cert_request_der_base64 = %q
+  validity_period = "24h"
+}
+`, test_authority_id, test_template_id, derBase64)
+}
+
+func TestCertificateRequestPEM(t *testing.T) {
+	der, err := csr(testCSR)
+	require.NoError(t, err)
+
+	csrPEM, err := certificateRequestPEM(base64.StdEncoding.EncodeToString(der) + "\n")
+	require.NoError(t, err)
+	got, err := csr(csrPEM)
+	require.NoError(t, err)
+	require.Equal(t, der, got)
+
+	_, err = certificateRequestPEM("not base64")
+	require.Error(t, err)
+	_, err = certificateRequestPEM(base64.StdEncoding.EncodeToString([]byte("not a request")))
+	require.Error(t, err)
+}
+
+func TestRenewalPeriod(t *testing.T) {
+	notBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.Add(90 * 24 * time.Hour)
+
+	require.Equal(t, time.Hour, renewalPeriod(time.Hour, types.Int64Null(), durationNull(), notBefore, notAfter))
+	require.Equal(t, 27*24*time.Hour, renewalPeriod(0, types.Int64Value(30), durationNull(), notBefore, notAfter))
+	require.Equal(t, 12*time.Hour, renewalPeriod(0, types.Int64Value(50), durationNull(), notBefore, notBefore.Add(24*time.Hour)))
+	// Rotating after 30 days is 60 days before expiration
+	require.Equal(t, 60*24*time.Hour, renewalPeriod(time.Hour, types.Int64Null(), durationString("30d"), notBefore, notAfter))
+	require.Equal(t, 27*24*time.Hour, renewalPeriod(0, types.Int64Value(30), durationString("80d"), notBefore, notAfter))
+	require.Equal(t, time.Hour, renewalPeriod(time.Hour, types.Int64Null(), durationString("1y"), notBefore, notAfter))
+}
+
+func TestQuotedList(t *testing.T) {
+	require.Equal(t, "", quotedList(nil))
+	require.Equal(t, "`a`", quotedList([]string{"a"}))
+	require.Equal(t, "`a`, `b` or `c`", quotedList([]string{"a", "b", "c"}))
+}
+
+func TestBuildSignOptionsSourceTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		sourceTag string
+		attribute types.String
+		want      string
+	}{
+		{name: "default", attribute: types.StringNull(), want: defaultSourceTag},
+		{name: "provider_meta", sourceTag: defaultSourceTag + " (module network)", attribute: types.StringNull(), want: defaultSourceTag + " (module network)"},
+		{name: "attribute", sourceTag: defaultSourceTag + " (module network)", attribute: types.StringValue("pipeline web"), want: "pipeline web"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := KeytosEzcaSslLeafCertResourceModel{
+				ValidityPeriod:                    durationString("24h"),
+				KeyUsages:                         types.SetUnknown(types.StringType),
+				ExtendedKeyUsages:                 types.SetUnknown(types.StringType),
+				OverwriteSubjectName:              types.ObjectUnknown(subjectNameAttributeTypes),
+				OverwriteSubjectNameStr:           distinguishedNameUnknown(),
+				AdditionalSubjectAlternativeNames: types.ObjectUnknown(subjectAlternativeNamesAttributeTypes),
+				SourceTag:                         tt.attribute,
+				sourceTag:                         tt.sourceTag,
+			}
+			var diags diag.Diagnostics
+			opts := buildSignOptions(context.Background(), &m, &diags)
+			require.False(t, diags.HasError(), "%v", diags)
+			require.Equal(t, tt.want, opts.SourceTag)
+		})
+	}
+}
+
+func TestBuildSignOptionsRequestUsages(t *testing.T) {
+	csrPEM := testCertificateRequestWithUsagesPEM(t)
+	for _, tt := range []struct {
+		forward  types.Bool
+		wantKUs  []ezca.KeyUsage
+		wantEKUs []ezca.ExtKeyUsage
+	}{
+		{forward: types.BoolNull(), wantKUs: []ezca.KeyUsage{ezca.KeyUsageDigitalSignature, ezca.KeyUsageKeyAgreement}, wantEKUs: []ezca.ExtKeyUsage{ezca.ExtKeyUsageClientAuth}},
+		{forward: types.BoolValue(false)},
+	} {
+		m := KeytosEzcaSslLeafCertResourceModel{
+			CertRequestPEM:                    types.StringValue(csrPEM),
+			ValidityPeriod:                    durationString("24h"),
+			KeyUsages:                         types.SetUnknown(types.StringType),
+			ExtendedKeyUsages:                 types.SetUnknown(types.StringType),
+			ForwardRequestUsages:              tt.forward,
+			OverwriteSubjectName:              types.ObjectUnknown(subjectNameAttributeTypes),
+			OverwriteSubjectNameStr:           distinguishedNameUnknown(),
+			AdditionalSubjectAlternativeNames: types.ObjectUnknown(subjectAlternativeNamesAttributeTypes),
+		}
+		var diags diag.Diagnostics
+		opts := buildSignOptions(context.Background(), &m, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, tt.wantKUs, opts.KeyUsages, "%s", tt.forward)
+		require.Equal(t, tt.wantEKUs, opts.ExtendedKeyUsages, "%s", tt.forward)
+		require.Len(t, m.KeyUsages.Elements(), 2)
+	}
+}
+
+func TestRevokePrevious(t *testing.T) {
+	for _, tt := range []struct {
+		value types.Bool
+		want  bool
+	}{
+		{value: types.BoolNull(), want: true},
+		{value: types.BoolValue(true), want: true},
+		{value: types.BoolValue(false), want: false},
+	} {
+		m := KeytosEzcaSslLeafCertResourceModel{RevokePreviousOnRenewal: tt.value}
+		require.Equal(t, tt.want, m.revokePrevious(), "%s", tt.value)
+	}
+}
+
+func TestDeleteAllowRevoke(t *testing.T) {
+	r := &KeytosEzcaSslLeafCertResource{}
+
+	var diags diag.Diagnostics
+	r.delete(context.Background(), &KeytosEzcaSslLeafCertResourceModel{
+		SkipRevokeOnDestroy: types.BoolValue(false),
+		AllowRevoke:         types.BoolValue(false),
+		CertSerialNumber:    types.StringValue("1234"),
+	}, &diags)
+	require.True(t, diags.HasError())
+	require.Equal(t, "Revocation Not Allowed", diags[0].Summary())
+
+	diags = nil
+	r.delete(context.Background(), &KeytosEzcaSslLeafCertResourceModel{
+		SkipRevokeOnDestroy: types.BoolValue(true),
+		AllowRevoke:         types.BoolValue(false),
+	}, &diags)
+	require.Empty(t, diags)
+}
+
+func TestWaitForOCSP(t *testing.T) {
+	_, cert := testSelfSignedCertificate(t, "leaf")
+	r := &KeytosEzcaSslLeafCertResource{}
+
+	var diags diag.Diagnostics
+	r.waitForOCSP(context.Background(), &KeytosEzcaSslLeafCertResourceModel{WaitForOCSP: types.BoolValue(false)}, []*x509.Certificate{cert}, &diags)
+	require.Empty(t, diags)
+
+	r.waitForOCSP(context.Background(), &KeytosEzcaSslLeafCertResourceModel{WaitForOCSP: types.BoolValue(true)}, []*x509.Certificate{cert}, &diags)
+	require.False(t, diags.HasError())
+	require.Equal(t, 1, diags.WarningsCount())
+
+	// Self-signed test certificates have no OCSP responder
+	diags = nil
+	r.waitForOCSP(context.Background(), &KeytosEzcaSslLeafCertResourceModel{WaitForOCSP: types.BoolValue(true)}, []*x509.Certificate{cert, cert}, &diags)
+	require.False(t, diags.HasError())
+	require.Equal(t, "Certificate Availability Not Checked", diags[0].Summary())
+}
+
+func TestBuildSignOptionsWithoutCommonName(t *testing.T) {
+	subjectName := map[string]attr.Value{}
+	for name, typ := range subjectNameAttributeTypes {
+		subjectName[name] = types.ListNull(types.StringType)
+		if typ == types.StringType {
+			subjectName[name] = types.StringNull()
+		}
+	}
+	subjectName["organization"] = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Example")})
+	m := KeytosEzcaSslLeafCertResourceModel{
+		ValidityPeriod:                    durationString("24h"),
+		KeyUsages:                         types.SetUnknown(types.StringType),
+		ExtendedKeyUsages:                 types.SetUnknown(types.StringType),
+		OverwriteSubjectName:              types.ObjectValueMust(subjectNameAttributeTypes, subjectName),
+		OverwriteSubjectNameStr:           distinguishedNameUnknown(),
+		AdditionalSubjectAlternativeNames: types.ObjectUnknown(subjectAlternativeNamesAttributeTypes),
+	}
+	var diags diag.Diagnostics
+	opts := buildSignOptions(context.Background(), &m, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, "O=Example", opts.SubjectName)
+}
+
+func TestReadBackfill(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+	_, cert := testIssuedCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "Test Leaf"}}, caKey, ca)
+	c, err := newEzcaClient([]string{"https://127.0.0.1:1"}, testCredential{}, retryPolicy{maxAttempts: 1})
+	require.NoError(t, err)
+	r := &KeytosEzcaSslLeafCertResource{client: c, refreshFailureMode: refreshFailureModeWarnAndKeepState}
+
+	// State of a certificate issued before the derived attributes were saved
+	m := KeytosEzcaSslLeafCertResourceModel{
+		AuthorityID:        types.StringValue(test_authority_id),
+		TemplateID:         types.StringValue(test_template_id),
+		CertPEM:            types.StringValue(encodeCertificatePEM(cert, defaultPEMLineLength, false)),
+		CAChainPEM:         types.ListValueMust(types.StringType, []attr.Value{types.StringValue(encodeCertificatePEM(ca, defaultPEMLineLength, false))}),
+		EarlyRenewalPeriod: durationNull(),
+		ValidityNotAfter:   types.StringValue(cert.NotAfter.Format(time.RFC3339)),
+		PEMLineLength:      types.Int64Value(defaultPEMLineLength),
+	}
+	var diags diag.Diagnostics
+	r.read(context.Background(), &m, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, base64.StdEncoding.EncodeToString(cert.Raw), m.CertDERBase64.ValueString())
+	require.Equal(t, "CN=Test CA", m.IssuerSubject.ValueString())
+	require.False(t, m.CertFullChainPEM.IsNull())
+}
+
+func TestSaveIssuedNames(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+	uri, err := url.Parse("spiffe://example.com/web")
+	require.NoError(t, err)
+	_, cert := testIssuedCertificate(t, &x509.Certificate{
+		DNSNames:       []string{"test.com", "www.test.com"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{uri},
+		EmailAddresses: []string{"admin@test.com"},
+	}, caKey, ca)
+
+	var m KeytosEzcaSslLeafCertResourceModel
+	saveIssuedNames(&m, cert)
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("test.com"), types.StringValue("www.test.com")}), m.IssuedDNSNames)
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("10.0.0.1")}), m.IssuedIPAddresses)
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("spiffe://example.com/web")}), m.IssuedURIs)
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("admin@test.com")}), m.IssuedEmailAddresses)
+
+	// Empty rather than null without names of a type
+	saveIssuedNames(&m, ca)
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{}), m.IssuedDNSNames)
+}
+
+func TestSaveAuthorityURLs(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+	_, cert := testIssuedCertificate(t, &x509.Certificate{
+		OCSPServer:            []string{"http://ocsp.test.com"},
+		IssuingCertificateURL: []string{"http://ca.test.com/ca.crt"},
+		CRLDistributionPoints: []string{"http://crl.test.com/ca.crl"},
+	}, caKey, ca)
+
+	var m KeytosEzcaSslLeafCertResourceModel
+	saveAuthorityURLs(&m, cert)
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("http://ocsp.test.com")}), m.OCSPServers)
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("http://ca.test.com/ca.crt")}), m.IssuingCertificateURLs)
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("http://crl.test.com/ca.crl")}), m.CRLDistributionPoints)
+
+	saveAuthorityURLs(&m, ca)
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{}), m.OCSPServers)
+}
+
+// testAuthority is a certificate authority recording the calls made to it.
+type testAuthority struct {
+	t         *testing.T
+	key       crypto.Signer
+	cert      *x509.Certificate
+	signErr   error
+	revokeErr error
+	calls     []string
+}
+
+func (a *testAuthority) Sign(ctx context.Context, csr []byte, opts *ezca.SignOptions) ([]*x509.Certificate, error) {
+	a.calls = append(a.calls, "sign")
+	if a.signErr != nil {
+		return nil, a.signErr
+	}
+	req, err := x509.ParseCertificateRequest(csr)
+	require.NoError(a.t, err)
+	_, cert := testIssuedCertificate(a.t, &x509.Certificate{
+		SerialNumber: big.NewInt(int64(len(a.calls) + 1)),
+		NotAfter:     time.Now().Add(opts.Duration),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		PublicKey:    req.PublicKey,
+	}, a.key, a.cert)
+	return []*x509.Certificate{cert, a.cert}, nil
+}
+
+func (a *testAuthority) RevokeWithThumbprint(ctx context.Context, thumbprint [sha1.Size]byte) error {
+	a.calls = append(a.calls, "revoke")
+	return a.revokeErr
+}
+
+func TestReissue(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+	key, err := generatePrivateKey(keyAlgorithmECDSAP256)
+	require.NoError(t, err)
+	csr, err := csr(testCertificateRequestPEM(t, key))
+	require.NoError(t, err)
+	_, oldCert := testSelfSignedCertificate(t, "old")
+	oldThumb := sha1.Sum(oldCert.Raw)
+
+	tests := []struct {
+		name           string
+		revokePrevious bool
+		signErr        error
+		revokeErr      error
+		calls          []string
+		issued         bool
+		errSummary     string
+	}{
+		{name: "revokes after signing", revokePrevious: true, calls: []string{"sign", "revoke"}, issued: true},
+		{name: "keeps previous", revokePrevious: false, calls: []string{"sign"}, issued: true},
+		{name: "signing fails", revokePrevious: true, signErr: errors.New("api error: quota exceeded"), calls: []string{"sign"}, errSummary: "Error Signing"},
+		{name: "revoking fails", revokePrevious: true, revokeErr: errors.New("api error: not found"), calls: []string{"sign", "revoke"}, issued: true, errSummary: "Error Revoking Certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &testAuthority{t: t, key: caKey, cert: ca, signErr: tt.signErr, revokeErr: tt.revokeErr}
+			newm := &KeytosEzcaSslLeafCertResourceModel{
+				CertPEM:                 types.StringUnknown(),
+				RevokePreviousOnRenewal: types.BoolValue(tt.revokePrevious),
+			}
+			oldm := &KeytosEzcaSslLeafCertResourceModel{
+				CertThumbprintHex: types.StringValue(hex.EncodeToString(oldThumb[:])),
+				CertSerialNumber:  types.StringValue("1"),
+			}
+
+			var diags diag.Diagnostics
+			certs := reissue(context.Background(), newm, oldm, a, func() (certificateRevoker, error) {
+				return a, nil
+			}, csr, &ezca.SignOptions{Duration: 24 * time.Hour}, 0, "Error Signing", &diags)
+			require.Equal(t, tt.calls, a.calls)
+			require.Equal(t, tt.issued, certs != nil)
+			require.Equal(t, tt.issued, !newm.CertPEM.IsUnknown())
+			if tt.errSummary == "" {
+				require.False(t, diags.HasError(), "%v", diags)
+			} else {
+				require.Equal(t, 1, diags.ErrorsCount(), "%v", diags)
+				require.Equal(t, tt.errSummary, diags.Errors()[0].Summary())
+			}
+		})
+	}
+}
+
+func TestSaveIssuer(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+	_, cert := testIssuedCertificate(t, &x509.Certificate{}, caKey, ca)
+
+	var m KeytosEzcaSslLeafCertResourceModel
+	saveIssuer(&m, cert, []*x509.Certificate{ca})
+	thumb := sha1.Sum(ca.Raw)
+	require.Equal(t, "CN=Test CA", m.IssuerSubject.ValueString())
+	require.Equal(t, hex.EncodeToString(thumb[:]), m.IssuerThumbprintHex.ValueString())
+	require.NotEmpty(t, ca.SubjectKeyId)
+	require.Equal(t, hex.EncodeToString(ca.SubjectKeyId), m.AuthorityKeyIdentifier.ValueString())
+
+	saveIssuer(&m, ca, nil)
+	require.True(t, m.IssuerThumbprintHex.IsNull())
+	require.True(t, m.AuthorityKeyIdentifier.IsNull())
+}
+
+func TestEncodeFullChainPEM(t *testing.T) {
+	rootKey, root := testSelfSignedCertificate(t, "Test Root")
+	intermediateKey, intermediate := testIssuedCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "Test Intermediate"}, IsCA: true}, rootKey, root)
+	_, cert := testIssuedCertificate(t, &x509.Certificate{SerialNumber: big.NewInt(3)}, intermediateKey, intermediate)
+
+	fullChain := encodeFullChainPEM(cert, []*x509.Certificate{intermediate, root}, defaultPEMLineLength, false)
+	certs, err := parseCertificatesPEM(fullChain)
+	require.NoError(t, err)
+	require.Equal(t, []*x509.Certificate{cert, intermediate}, certs)
+	require.True(t, strings.HasPrefix(fullChain, encodeCertificatePEM(cert, defaultPEMLineLength, false)))
+}
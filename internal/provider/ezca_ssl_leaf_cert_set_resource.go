@@ -0,0 +1,563 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultLeafCertSetParallelism = 10
+	maxLeafCertSetParallelism     = 50
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &KeytosEzcaSslLeafCertSetResource{}
+var _ resource.ResourceWithModifyPlan = &KeytosEzcaSslLeafCertSetResource{}
+
+func NewKeytosEzcaSslLeafCertSetResource() resource.Resource {
+	return &KeytosEzcaSslLeafCertSetResource{}
+}
+
+// KeytosEzcaSslLeafCertSetResource defines the resource implementation. Each
+// certificate of the set is signed like a KeytosEzcaSslLeafCertResource
+// certificate, concurrently.
+type KeytosEzcaSslLeafCertSetResource struct {
+	leaf KeytosEzcaSslLeafCertResource
+}
+
+// KeytosEzcaSslLeafCertSetResourceModel describes the resource data model.
+type KeytosEzcaSslLeafCertSetResourceModel struct {
+	AuthorityID        types.String  `tfsdk:"authority_id"`
+	TemplateID         types.String  `tfsdk:"template_id"`
+	CertRequests       types.Map     `tfsdk:"cert_requests"`
+	ValidityPeriod     durationValue `tfsdk:"validity_period"`
+	EarlyRenewalPeriod durationValue `tfsdk:"early_renewal_period"`
+	Parallelism        types.Int64   `tfsdk:"parallelism"`
+
+	Certificates    types.Map  `tfsdk:"certificates"`
+	CAChainPEM      types.List `tfsdk:"ca_chain_pem"`
+	ReadyForRenewal types.Bool `tfsdk:"ready_for_renewal"`
+}
+
+// leafCertSetCertificate describes a certificate of the set.
+type leafCertSetCertificate struct {
+	CertPEM           types.String `tfsdk:"cert_pem"`
+	CertThumbprintHex types.String `tfsdk:"cert_thumbprint_hex"`
+	CertSerialNumber  types.String `tfsdk:"cert_serial_number"`
+	ValidityNotAfter  types.String `tfsdk:"validity_not_after"`
+}
+
+var leafCertSetCertificateType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"cert_pem":            types.StringType,
+	"cert_thumbprint_hex": types.StringType,
+	"cert_serial_number":  types.StringType,
+	"validity_not_after":  types.StringType,
+}}
+
+func (r *KeytosEzcaSslLeafCertSetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ezca_ssl_leaf_cert_set"
+}
+
+func (r *KeytosEzcaSslLeafCertSetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	requiresReplace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates certificates for a map of certificate requests that are issued by the same EZCA SSL authority template, concurrently. This is much faster than a `keytos_ezca_ssl_leaf_cert` resource per request when issuing hundreds of certificates. Adding a request issues its certificate, and removing one revokes it. Certificates are revoked when the resource is deleted, and renewed in place when ready for renewal. Certificates whose request changed or that are renewed are revoked once their new certificate is issued.",
+
+		Attributes: map[string]schema.Attribute{
+			"authority_id": schema.StringAttribute{
+				MarkdownDescription: "EZCA SSL authority identifier",
+				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"template_id": schema.StringAttribute{
+				MarkdownDescription: "EZCA authority SSL template identifier",
+				Required:            true,
+				Validators: []validator.String{
+					uuidValidator{},
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"cert_requests": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Certificate requests in PEM format, by a key of your choosing that also keys `certificates`. The certificates are issued for the subject and subject alternative names of their request.",
+				Required:            true,
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+				},
+			},
+			"validity_period": schema.StringAttribute{
+				CustomType:          durationType{},
+				MarkdownDescription: "Validity period that the certificates will remain valid for. " + durationUnitsDescription,
+				Required:            true,
+				PlanModifiers:       requiresReplace,
+			},
+			"early_renewal_period": schema.StringAttribute{
+				CustomType:          durationType{},
+				MarkdownDescription: "Resource will consider a certificate ready for renewal early by the duration defined here. Accepts the same duration units as `validity_period`.",
+				Optional:            true,
+			},
+			"parallelism": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of certificates issued or revoked at the same time, between 1 and %d. Defaults to %d.", maxLeafCertSetParallelism, defaultLeafCertSetParallelism),
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(defaultLeafCertSetParallelism),
+				Validators: []validator.Int64{
+					int64validator.Between(1, maxLeafCertSetParallelism),
+				},
+			},
+
+			"certificates": schema.MapNestedAttribute{
+				MarkdownDescription: "Issued certificates, by the key of their request in `cert_requests`.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"cert_pem": schema.StringAttribute{
+							MarkdownDescription: "Certificate data in PEM format.",
+							Computed:            true,
+						},
+						"cert_thumbprint_hex": schema.StringAttribute{
+							MarkdownDescription: "Certificate thumbprint. This is a SHA-1 sum of the raw certificate contents.",
+							Computed:            true,
+						},
+						"cert_serial_number": schema.StringAttribute{
+							MarkdownDescription: "Certificate serial number.",
+							Computed:            true,
+						},
+						"validity_not_after": schema.StringAttribute{
+							MarkdownDescription: "Time after which the certificate is not valid as an RFC3339 timestamp.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"ca_chain_pem": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Authority chain of the certificates, as a list of certificates in PEM format from the issuing authority up to the root.",
+				Computed:            true,
+			},
+			"ready_for_renewal": schema.BoolAttribute{
+				MarkdownDescription: "True when a certificate is expired or when in the early renewal period. When true, the next plan renews the certificates ready for renewal.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *KeytosEzcaSslLeafCertSetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.leaf.Configure(ctx, req, resp)
+}
+
+func (r *KeytosEzcaSslLeafCertSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data KeytosEzcaSslLeafCertSetResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tag := sourceTag(ctx, req.ProviderMeta, &resp.Diagnostics)
+	var requests map[string]string
+	resp.Diagnostics.Append(data.CertRequests.ElementsAs(ctx, &requests, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	issued := r.issue(ctx, &data, tag, requests, slices.Sorted(maps.Keys(requests)), &resp.Diagnostics)
+	if len(issued) == 0 {
+		return
+	}
+
+	// Certificates issued when others could not be must still be saved, the
+	// resource being tainted
+	certs := make(map[string]leafCertSetCertificate, len(issued))
+	saved := make(map[string]string, len(issued))
+	for key, m := range issued {
+		certs[key] = leafCertSetCertificateOf(&m)
+		saved[key] = requests[key]
+	}
+	data.save(ctx, certs, saved, issued, types.ListNull(types.StringType), &resp.Diagnostics)
+
+	tflog.Trace(ctx, "created a resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeytosEzcaSslLeafCertSetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data KeytosEzcaSslLeafCertSetResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Unlike keytos_ezca_ssl_leaf_cert, certificates are not looked up in
+	// EZCA, which would not scale to large sets
+	var certs map[string]leafCertSetCertificate
+	resp.Diagnostics.Append(data.Certificates.ElementsAs(ctx, &certs, false)...)
+	erp, err := parseEarlyRenewalPeriod(data.EarlyRenewalPeriod)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("early_renewal_period"), "Invalid Early Renewal Period", fmt.Sprintf("Invalid duration string: %v", err))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ReadyForRenewal = types.BoolValue(len(leafCertSetReadyForRenewal(certs, erp)) > 0)
+
+	tflog.Trace(ctx, "read and updated the resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeytosEzcaSslLeafCertSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var newm, oldm KeytosEzcaSslLeafCertSetResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newm)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &oldm)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tag := sourceTag(ctx, req.ProviderMeta, &resp.Diagnostics)
+
+	var newRequests, oldRequests map[string]string
+	var oldCerts map[string]leafCertSetCertificate
+	resp.Diagnostics.Append(newm.CertRequests.ElementsAs(ctx, &newRequests, false)...)
+	resp.Diagnostics.Append(oldm.CertRequests.ElementsAs(ctx, &oldRequests, false)...)
+	resp.Diagnostics.Append(oldm.Certificates.ElementsAs(ctx, &oldCerts, false)...)
+	erp, err := parseEarlyRenewalPeriod(newm.EarlyRenewalPeriod)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("early_renewal_period"), "Invalid Early Renewal Period", fmt.Sprintf("Invalid duration string: %v", err))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	changes := planLeafCertSet(newRequests, oldRequests, oldCerts, erp)
+	certs := make(map[string]leafCertSetCertificate, len(newRequests))
+	saved := make(map[string]string, len(newRequests))
+	for _, key := range changes.keep {
+		certs[key] = oldCerts[key]
+		saved[key] = oldRequests[key]
+	}
+
+	issued := r.issue(ctx, &newm, tag, newRequests, changes.issue, &resp.Diagnostics)
+	revoke := slices.Clone(changes.revoke)
+	for _, key := range changes.issue {
+		m, ok := issued[key]
+		if !ok {
+			// The previous certificate is kept valid and the request is
+			// saved as it was, so that the next plan tries again
+			if old, ok := oldCerts[key]; ok {
+				certs[key] = old
+				saved[key] = oldRequests[key]
+			}
+			continue
+		}
+		certs[key] = leafCertSetCertificateOf(&m)
+		saved[key] = newRequests[key]
+		if _, ok := oldCerts[key]; ok {
+			revoke = append(revoke, key)
+		}
+	}
+	tflog.Trace(ctx, "issued certificates", map[string]any{"count": len(issued)})
+
+	// Certificates are only revoked once their replacement is issued
+	for _, key := range r.revoke(ctx, &newm, oldCerts, revoke, &resp.Diagnostics) {
+		// Removed certificates that could not be revoked are kept
+		if _, ok := newRequests[key]; !ok {
+			certs[key] = oldCerts[key]
+			saved[key] = oldRequests[key]
+		}
+	}
+
+	newm.save(ctx, certs, saved, issued, oldm.CAChainPEM, &resp.Diagnostics)
+
+	tflog.Trace(ctx, "updated the resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &newm)...)
+}
+
+func (r *KeytosEzcaSslLeafCertSetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.leaf.fipsMode && !req.Plan.Raw.IsNull() {
+		var requests types.Map
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("cert_requests"), &requests)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Unknown requests are checked again during apply
+		for key, v := range requests.Elements() {
+			csrPEM, ok := v.(types.String)
+			if !ok || csrPEM.IsUnknown() {
+				continue
+			}
+			var d diag.Diagnostics
+			checkFIPSCertificateRequest(csrPEM.ValueString(), &d)
+			for _, dd := range d {
+				resp.Diagnostics.Append(diag.WithPath(path.Root("cert_requests").AtMapKey(key), dd))
+			}
+		}
+	}
+
+	// Nothing to renew on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var certs types.Map
+	var erpStr durationValue
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("certificates"), &certs)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("early_renewal_period"), &erpStr)...)
+	if resp.Diagnostics.HasError() || erpStr.IsUnknown() {
+		return
+	}
+	var stateCerts map[string]leafCertSetCertificate
+	resp.Diagnostics.Append(certs.ElementsAs(ctx, &stateCerts, false)...)
+	erp, err := parseEarlyRenewalPeriod(erpStr)
+	if resp.Diagnostics.HasError() || err != nil || len(leafCertSetReadyForRenewal(stateCerts, erp)) == 0 {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificates"), types.MapUnknown(leafCertSetCertificateType))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ca_chain_pem"), types.ListUnknown(types.StringType))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ready_for_renewal"), types.BoolUnknown())...)
+}
+
+func (r *KeytosEzcaSslLeafCertSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data KeytosEzcaSslLeafCertSetResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var certs map[string]leafCertSetCertificate
+	resp.Diagnostics.Append(data.Certificates.ElementsAs(ctx, &certs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Revoke every certificate even if some of them fail
+	r.revoke(ctx, &data, certs, slices.Sorted(maps.Keys(certs)), &resp.Diagnostics)
+}
+
+// issue signs the requests of the keys concurrently, returning the models of
+// the issued certificates by key. Keys whose certificate could not be issued
+// are left out.
+func (r *KeytosEzcaSslLeafCertSetResource) issue(ctx context.Context, m *KeytosEzcaSslLeafCertSetResourceModel, tag string, requests map[string]string, keys []string, diags *diag.Diagnostics) map[string]KeytosEzcaSslLeafCertResourceModel {
+	var mu sync.Mutex
+	issued := make(map[string]KeytosEzcaSslLeafCertResourceModel, len(keys))
+	r.run(ctx, m, keys, func(ctx context.Context, c *sslAuthorityClient, key string, diags *diag.Diagnostics) {
+		l := m.leafModel(types.StringValue(requests[key]))
+		l.sourceTag = tag
+		r.leaf.sign(ctx, c, &l, diags)
+		// A certificate may have been issued even when other errors occurred
+		if l.CertPEM.IsUnknown() {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		issued[key] = l
+	}, diags)
+	return issued
+}
+
+// revoke revokes the certificates of the keys concurrently, returning the
+// keys of those that could not be revoked.
+func (r *KeytosEzcaSslLeafCertSetResource) revoke(ctx context.Context, m *KeytosEzcaSslLeafCertSetResourceModel, certs map[string]leafCertSetCertificate, keys []string, diags *diag.Diagnostics) []string {
+	var mu sync.Mutex
+	var failed []string
+	r.run(ctx, m, keys, func(ctx context.Context, c *sslAuthorityClient, key string, diags *diag.Diagnostics) {
+		l := m.leafModel(types.StringNull())
+		l.CertPEM = certs[key].CertPEM
+		l.CertThumbprintHex = certs[key].CertThumbprintHex
+		thumb, err := revocationThumbprint(&l)
+		if err == nil {
+			err = c.RevokeWithThumbprint(ctx, thumb)
+		}
+		if err != nil {
+			diags.AddError("Error Revoking Certificate", fmt.Sprintf("Encountered an error when trying to revoke the certificate with serial number %s: %s", certs[key].CertSerialNumber.ValueString(), ezcaErrorDetail(err)))
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, key)
+		}
+	}, diags)
+	slices.Sort(failed)
+	return failed
+}
+
+// run calls f for each key, at most parallelism calls at a time. Each worker
+// creates its own EZCA client once for all the keys it handles, and the
+// diagnostics of each call are attributed to the request of its key.
+func (r *KeytosEzcaSslLeafCertSetResource) run(ctx context.Context, m *KeytosEzcaSslLeafCertSetResourceModel, keys []string, f func(ctx context.Context, c *sslAuthorityClient, key string, diags *diag.Diagnostics), diags *diag.Diagnostics) {
+	workers := min(int(m.Parallelism.ValueInt64()), len(keys))
+	if workers <= 0 {
+		workers = min(defaultLeafCertSetParallelism, len(keys))
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := r.workerClient(ctx, m)
+			for key := range jobs {
+				var d diag.Diagnostics
+				if err != nil {
+					d.AddError("Error creating SSL authority client", fmt.Sprintf("Errors encountered creating SSL authority client: %s", ezcaErrorDetail(err)))
+				} else {
+					f(ctx, c, key, &d)
+				}
+				mu.Lock()
+				for _, dd := range d {
+					diags.Append(diag.WithPath(path.Root("cert_requests").AtMapKey(key), dd))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, key := range keys {
+		jobs <- key
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// workerClient returns an SSL authority client of its own for a worker of
+// run.
+func (r *KeytosEzcaSslLeafCertSetResource) workerClient(ctx context.Context, m *KeytosEzcaSslLeafCertSetResourceModel) (*sslAuthorityClient, error) {
+	client, err := r.leaf.client.clone(r.leaf.credential)
+	if err != nil {
+		return nil, err
+	}
+	w := r.leaf
+	w.client = client
+	l := m.leafModel(types.StringNull())
+	return w.sslAuthorityClient(ctx, &l)
+}
+
+// leafModel returns the model of the certificate of the set for the request.
+// Attributes the set does not configure are unknown so that the leaf
+// certificate defaults apply.
+func (m *KeytosEzcaSslLeafCertSetResourceModel) leafModel(certRequestPEM types.String) KeytosEzcaSslLeafCertResourceModel {
+	l := KeytosEzcaSslLeafCertResourceModel{
+		AuthorityID:                       m.AuthorityID,
+		TemplateID:                        m.TemplateID,
+		CertRequestPEM:                    certRequestPEM,
+		ValidityPeriod:                    m.ValidityPeriod,
+		KeyUsages:                         types.SetUnknown(types.StringType),
+		ExtendedKeyUsages:                 types.SetUnknown(types.StringType),
+		OverwriteSubjectName:              types.ObjectUnknown(subjectNameAttributeTypes),
+		OverwriteSubjectNameStr:           distinguishedNameUnknown(),
+		AdditionalSubjectAlternativeNames: types.ObjectUnknown(subjectAlternativeNamesAttributeTypes),
+		EarlyRenewalPeriod:                m.EarlyRenewalPeriod,
+		PEMLineLength:                     types.Int64Value(defaultPEMLineLength),
+		PEMExplanatoryText:                types.BoolValue(false),
+		CertPEM:                           types.StringUnknown(),
+	}
+	if l.EarlyRenewalPeriod.IsNull() {
+		l.EarlyRenewalPeriod = durationUnknown()
+	}
+	return l
+}
+
+// save saves the certificates of the set and the requests they were issued
+// for into the model. The authority chain is the one of the issued
+// certificates, or chain when none was issued.
+func (m *KeytosEzcaSslLeafCertSetResourceModel) save(ctx context.Context, certs map[string]leafCertSetCertificate, requests map[string]string, issued map[string]KeytosEzcaSslLeafCertResourceModel, chain types.List, diags *diag.Diagnostics) {
+	var d diag.Diagnostics
+	m.Certificates, d = types.MapValueFrom(ctx, leafCertSetCertificateType, certs)
+	diags.Append(d...)
+	m.CertRequests, d = types.MapValueFrom(ctx, types.StringType, requests)
+	diags.Append(d...)
+	m.CAChainPEM = chain
+	if keys := slices.Sorted(maps.Keys(issued)); len(keys) > 0 {
+		m.CAChainPEM = issued[keys[0]].CAChainPEM
+	}
+	erp, err := parseEarlyRenewalPeriod(m.EarlyRenewalPeriod)
+	if err != nil {
+		diags.AddAttributeError(path.Root("early_renewal_period"), "Invalid Early Renewal Period", fmt.Sprintf("Invalid duration string: %v", err))
+		return
+	}
+	m.ReadyForRenewal = types.BoolValue(len(leafCertSetReadyForRenewal(certs, erp)) > 0)
+}
+
+func leafCertSetCertificateOf(m *KeytosEzcaSslLeafCertResourceModel) leafCertSetCertificate {
+	return leafCertSetCertificate{
+		CertPEM:           m.CertPEM,
+		CertThumbprintHex: m.CertThumbprintHex,
+		CertSerialNumber:  m.CertSerialNumber,
+		ValidityNotAfter:  m.ValidityNotAfter,
+	}
+}
+
+// leafCertSetReadyForRenewal returns the sorted keys of the certificates
+// ready for renewal.
+func leafCertSetReadyForRenewal(certs map[string]leafCertSetCertificate, erp time.Duration) []string {
+	var keys []string
+	for key, c := range certs {
+		notAfter, err := time.Parse(time.RFC3339, c.ValidityNotAfter.ValueString())
+		if err != nil || readyForRenewal(notAfter, erp) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// leafCertSetChanges are the keys of the certificates of a set to keep as
+// they are, to issue and to revoke.
+type leafCertSetChanges struct {
+	keep   []string
+	issue  []string
+	revoke []string
+}
+
+// planLeafCertSet plans the changes to the certificates of a set for the
+// requests: certificates are issued for new and changed requests and when
+// ready for renewal, and revoked when their request was removed.
+func planLeafCertSet(newRequests, oldRequests map[string]string, oldCerts map[string]leafCertSetCertificate, erp time.Duration) leafCertSetChanges {
+	var changes leafCertSetChanges
+	renew := leafCertSetReadyForRenewal(oldCerts, erp)
+	for _, key := range slices.Sorted(maps.Keys(newRequests)) {
+		_, ok := oldCerts[key]
+		if ok && oldRequests[key] == newRequests[key] && !slices.Contains(renew, key) {
+			changes.keep = append(changes.keep, key)
+		} else {
+			changes.issue = append(changes.issue, key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(oldCerts)) {
+		if _, ok := newRequests[key]; !ok {
+			changes.revoke = append(changes.revoke, key)
+		}
+	}
+	return changes
+}
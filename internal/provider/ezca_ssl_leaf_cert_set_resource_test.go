@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/markeytos/terraform-provider-keytos/internal/acctest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccKeytosEzcaSslLeafCertSet(t *testing.T) {
+	key, err := generatePrivateKey(keyAlgorithmECDSAP256)
+	require.NoError(t, err)
+	otherCSR := testCertificateRequestPEM(t, key)
+	certPEMRegexp := regexp.MustCompile(`-----BEGIN CERTIFICATE-----[\r\n]+([A-Za-z0-9+/=\r\n]+)[\r\n]+-----END CERTIFICATE-----`)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccKeytosEzcaSslLeafCertSetConfig(map[string]string{"a": testCSR}, 0),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value`),
+			},
+			// Create and Read testing
+			{
+				Config: testAccKeytosEzcaSslLeafCertSetConfig(map[string]string{"a": testCSR, "b": otherCSR}, 2),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert_set.test",
+						tfjsonpath.New("certificates").AtMapKey("a").AtMapKey("cert_pem"),
+						knownvalue.StringRegexp(certPEMRegexp),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert_set.test",
+						tfjsonpath.New("certificates").AtMapKey("b").AtMapKey("cert_pem"),
+						knownvalue.StringRegexp(certPEMRegexp),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert_set.test",
+						tfjsonpath.New("ready_for_renewal"),
+						knownvalue.Bool(false),
+					),
+				},
+			},
+			// Removing a request revokes its certificate
+			{
+				Config: testAccKeytosEzcaSslLeafCertSetConfig(map[string]string{"a": testCSR}, 2),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_ssl_leaf_cert_set.test",
+						tfjsonpath.New("certificates"),
+						knownvalue.MapSizeExact(1),
+					),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccKeytosEzcaSslLeafCertSetConfig(requests map[string]string, parallelism int) string {
+	var sb strings.Builder
+	for key, csr := range requests {
+		fmt.Fprintf(&sb, "    %s = %q\n", key, csr)
+	}
+	return fmt.Sprintf(`
+resource "keytos_ezca_ssl_leaf_cert_set" "test" {
+  authority_id = %q
+  template_id = %q
+  validity_period = "24h"
+  parallelism = %d
+  cert_requests = {
+%s  }
+}
+`, test_authority_id, test_template_id, parallelism, sb.String())
+}
+
+func TestAccKeytosEzcaSslLeafCertSet_fipsMode(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "keytos" {
+  fips_mode = true
+}
+` + testAccKeytosEzcaSslLeafCertSetConfig(map[string]string{"a": testCSR, "b": testCertificateRequestPEM(t, key)}, 2),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Non-FIPS Certificate Request.*\n\s*\d+:\s+b = `),
+			},
+		},
+	})
+}
+
+func TestPlanLeafCertSet(t *testing.T) {
+	valid := leafCertSetCertificate{ValidityNotAfter: types.StringValue(time.Now().Add(48 * time.Hour).Format(time.RFC3339))}
+	expiring := leafCertSetCertificate{ValidityNotAfter: types.StringValue(time.Now().Add(time.Hour).Format(time.RFC3339))}
+
+	changes := planLeafCertSet(
+		map[string]string{"kept": "csr", "changed": "new csr", "renewed": "csr", "added": "csr"},
+		map[string]string{"kept": "csr", "changed": "csr", "renewed": "csr", "removed": "csr"},
+		map[string]leafCertSetCertificate{"kept": valid, "changed": valid, "renewed": expiring, "removed": valid},
+		24*time.Hour,
+	)
+	require.Equal(t, []string{"kept"}, changes.keep)
+	require.Equal(t, []string{"added", "changed", "renewed"}, changes.issue)
+	require.Equal(t, []string{"removed"}, changes.revoke)
+}
+
+func TestLeafCertSetLeafModel(t *testing.T) {
+	m := KeytosEzcaSslLeafCertSetResourceModel{
+		AuthorityID:        types.StringValue(test_authority_id),
+		TemplateID:         types.StringValue(test_template_id),
+		ValidityPeriod:     durationString("24h"),
+		EarlyRenewalPeriod: durationNull(),
+	}
+
+	l := m.leafModel(types.StringValue(testCSR))
+	var diags diag.Diagnostics
+	opts := buildSignOptions(context.Background(), &l, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, 24*time.Hour, opts.Duration)
+	require.True(t, l.CertPEM.IsUnknown())
+}
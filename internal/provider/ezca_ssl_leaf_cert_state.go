@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// sslCertSchemaVersion is the schema version of the resources issuing
+// certificates from an EZCA SSL authority. Bump it along with an upgrader
+// in UpgradeState whenever the state of existing resources would no longer
+// decode, or would plan changes, with the new schema.
+//
+//   - 0: initial schema.
+//   - 1: key_usages and extended_key_usages are sets rather than lists.
+const sslCertSchemaVersion = 1
+
+// sslCertStateDefaults holds the defaults of the attributes added to schema
+// version 0 over time, missing from the state of the resources created
+// before they were.
+var sslCertStateDefaults = map[string]any{
+	"pem_line_length":            json.Number(fmt.Sprint(defaultPEMLineLength)),
+	"pem_explanatory_text":       false,
+	"skip_revoke_on_destroy":     false,
+	"allow_revoke":               true,
+	"detect_revocation":          false,
+	"revoke_previous_on_renewal": true,
+	"wait_for_ocsp":              false,
+	"require_sct":                false,
+	// Usages were not forwarded before, and forwarding them would issue new
+	// certificates on the next update
+	"forward_request_usages": false,
+}
+
+// priorStateBoolDefault plans an unconfigured attribute as in the prior
+// state, defaulting to value on create and to prior when the state predates
+// the attribute. Unlike a schema default, it lets the resources created
+// before the attribute was added keep their behavior.
+type priorStateBoolDefault struct {
+	value bool
+	prior bool
+}
+
+func (m priorStateBoolDefault) Description(ctx context.Context) string {
+	return fmt.Sprintf("defaults to %t, or to the prior state value", m.value)
+}
+
+func (m priorStateBoolDefault) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m priorStateBoolDefault) PlanModifyBool(ctx context.Context, req planmodifier.BoolRequest, resp *planmodifier.BoolResponse) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+	switch {
+	case req.State.Raw.IsNull():
+		resp.PlanValue = types.BoolValue(m.value)
+	case req.StateValue.IsNull():
+		resp.PlanValue = types.BoolValue(m.prior)
+	default:
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// UpgradeState upgrades the state of the prior schema versions to the
+// current one. The schema of every prior version is not kept: upgraders
+// edit the JSON encoded state, attributes missing from it decoding as null.
+func (r *KeytosEzcaSslLeafCertResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {StateUpgrader: rawStateUpgrader(upgradeSslCertStateV0)},
+	}
+}
+
+// rawStateUpgrader returns a state upgrader applying upgrade to the JSON
+// encoded state.
+func rawStateUpgrader(upgrade func(state map[string]any)) func(context.Context, resource.UpgradeStateRequest, *resource.UpgradeStateResponse) {
+	return func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+		if req.RawState == nil {
+			resp.Diagnostics.AddError("Unable to Upgrade Resource State", "The prior state is missing. Please report this issue to the provider developers.")
+			return
+		}
+		var state map[string]any
+		d := json.NewDecoder(bytes.NewReader(req.RawState.JSON))
+		d.UseNumber()
+		if err := d.Decode(&state); err != nil {
+			resp.Diagnostics.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Could not decode the prior state: %v", err))
+			return
+		}
+		upgrade(state)
+		raw, err := json.Marshal(state)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Could not encode the upgraded state: %v", err))
+			return
+		}
+		resp.DynamicValue = &tfprotov6.DynamicValue{JSON: raw}
+	}
+}
+
+// upgradeSslCertStateV0 upgrades the state of schema version 0, where the
+// key usages were lists. Lists and sets share their JSON encoding, so only
+// the duplicate usages a set cannot hold are removed. The defaults of the
+// attributes added to version 0 are filled in, so that upgraded resources
+// plan no change.
+func upgradeSslCertStateV0(state map[string]any) {
+	for _, name := range []string{"key_usages", "extended_key_usages"} {
+		usages, ok := state[name].([]any)
+		if !ok {
+			continue
+		}
+		unique := make([]any, 0, len(usages))
+		for _, u := range usages {
+			if !slices.Contains(unique, u) {
+				unique = append(unique, u)
+			}
+		}
+		state[name] = unique
+	}
+	for name, v := range sslCertStateDefaults {
+		if state[name] == nil {
+			state[name] = v
+		}
+	}
+}
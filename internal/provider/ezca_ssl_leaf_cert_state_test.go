@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeSslCertStateV0(t *testing.T) {
+	ctx := context.Background()
+	v0 := []byte(`{
+		"authority_id": "` + test_authority_id + `",
+		"template_id": "` + test_template_id + `",
+		"validity_period": "24h",
+		"key_usages": ["Digital Signature", "Digital Signature"],
+		"extended_key_usages": ["1.3.6.1.5.5.7.3.2", "1.3.6.1.5.5.7.3.1"],
+		"overwrite_subject_name": null,
+		"cert_serial_number": "1234"
+	}`)
+
+	for _, r := range []resource.ResourceWithUpgradeState{&KeytosEzcaSslLeafCertResource{}, &KeytosEzcaSslCertResource{}} {
+		var schemaResp resource.SchemaResponse
+		r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+		require.EqualValues(t, sslCertSchemaVersion, schemaResp.Schema.Version)
+
+		upgraders := r.UpgradeState(ctx)
+		require.Len(t, upgraders, sslCertSchemaVersion)
+		var resp resource.UpgradeStateResponse
+		upgraders[0].StateUpgrader(ctx, resource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: v0}}, &resp)
+		require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+		// The upgraded state decodes with the current schema
+		raw, err := resp.DynamicValue.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+		require.NoError(t, err)
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+		var keyUsages types.Set
+		var revokePrevious types.Bool
+		var lineLength types.Int64
+		var forwardUsages types.Bool
+		var waitForOCSP types.Bool
+		var requireSCT types.Bool
+		diags := state.GetAttribute(ctx, path.Root("key_usages"), &keyUsages)
+		diags.Append(state.GetAttribute(ctx, path.Root("revoke_previous_on_renewal"), &revokePrevious)...)
+		diags.Append(state.GetAttribute(ctx, path.Root("pem_line_length"), &lineLength)...)
+		diags.Append(state.GetAttribute(ctx, path.Root("forward_request_usages"), &forwardUsages)...)
+		diags.Append(state.GetAttribute(ctx, path.Root("wait_for_ocsp"), &waitForOCSP)...)
+		diags.Append(state.GetAttribute(ctx, path.Root("require_sct"), &requireSCT)...)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, types.SetValueMust(types.StringType, []attr.Value{types.StringValue("Digital Signature")}), keyUsages)
+		require.True(t, revokePrevious.ValueBool())
+		require.EqualValues(t, defaultPEMLineLength, lineLength.ValueInt64())
+		require.Equal(t, types.BoolValue(false), forwardUsages)
+		require.Equal(t, types.BoolValue(false), waitForOCSP)
+		require.Equal(t, types.BoolValue(false), requireSCT)
+
+		// Every attribute with a default has it in the upgraded state, so
+		// that upgraded resources plan no change
+		for name, a := range schemaResp.Schema.Attributes {
+			expect := schemaDefault(ctx, t, a)
+			if expect == nil {
+				continue
+			}
+			want, err := expect.ToTerraformValue(ctx)
+			require.NoError(t, err, name)
+			got, err := state.Raw.ApplyTerraform5AttributePathStep(tftypes.AttributeName(name))
+			require.NoError(t, err, name)
+			require.True(t, want.Equal(got.(tftypes.Value)), "%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+// schemaDefault returns the default value of the attribute, nil when it has
+// none.
+func schemaDefault(ctx context.Context, t *testing.T, a schema.Attribute) attr.Value {
+	t.Helper()
+	switch a := a.(type) {
+	case schema.BoolAttribute:
+		if a.Default == nil {
+			return nil
+		}
+		var resp defaults.BoolResponse
+		a.Default.DefaultBool(ctx, defaults.BoolRequest{}, &resp)
+		return resp.PlanValue
+	case schema.Int64Attribute:
+		if a.Default == nil {
+			return nil
+		}
+		var resp defaults.Int64Response
+		a.Default.DefaultInt64(ctx, defaults.Int64Request{}, &resp)
+		return resp.PlanValue
+	case schema.StringAttribute:
+		if a.Default == nil {
+			return nil
+		}
+		var resp defaults.StringResponse
+		a.Default.DefaultString(ctx, defaults.StringRequest{}, &resp)
+		return resp.PlanValue
+	case schema.SetAttribute:
+		if a.Default == nil {
+			return nil
+		}
+		var resp defaults.SetResponse
+		a.Default.DefaultSet(ctx, defaults.SetRequest{}, &resp)
+		return resp.PlanValue
+	}
+	return nil
+}
+
+func TestPriorStateBoolDefault(t *testing.T) {
+	ctx := context.Background()
+	m := priorStateBoolDefault{value: true, prior: false}
+	existing := tfsdk.State{Raw: tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})}
+
+	tests := []struct {
+		name   string
+		req    planmodifier.BoolRequest
+		expect types.Bool
+	}{
+		{
+			name:   "create",
+			req:    planmodifier.BoolRequest{ConfigValue: types.BoolNull(), StateValue: types.BoolNull(), PlanValue: types.BoolUnknown()},
+			expect: types.BoolValue(true),
+		},
+		{
+			// State written before the attribute was added, which the
+			// upgraders do not reach when already at the current version
+			name:   "state predates attribute",
+			req:    planmodifier.BoolRequest{State: existing, ConfigValue: types.BoolNull(), StateValue: types.BoolNull(), PlanValue: types.BoolUnknown()},
+			expect: types.BoolValue(false),
+		},
+		{
+			name:   "keeps state",
+			req:    planmodifier.BoolRequest{State: existing, ConfigValue: types.BoolNull(), StateValue: types.BoolValue(true), PlanValue: types.BoolUnknown()},
+			expect: types.BoolValue(true),
+		},
+		{
+			name:   "configured",
+			req:    planmodifier.BoolRequest{State: existing, ConfigValue: types.BoolValue(true), StateValue: types.BoolValue(false), PlanValue: types.BoolValue(true)},
+			expect: types.BoolValue(true),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := planmodifier.BoolResponse{PlanValue: tt.req.PlanValue}
+			m.PlanModifyBool(ctx, tt.req, &resp)
+			require.Equal(t, tt.expect, resp.PlanValue)
+		})
+	}
+}
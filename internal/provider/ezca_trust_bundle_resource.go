@@ -0,0 +1,275 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &KeytosEzcaTrustBundleResource{}
+var _ resource.ResourceWithModifyPlan = &KeytosEzcaTrustBundleResource{}
+
+func NewKeytosEzcaTrustBundleResource() resource.Resource {
+	return &KeytosEzcaTrustBundleResource{}
+}
+
+// KeytosEzcaTrustBundleResource defines the resource implementation. The
+// bundle is assembled by the provider from authority certificates already in
+// the configuration, such as the ca_chain_pem of issued certificates.
+type KeytosEzcaTrustBundleResource struct{}
+
+// KeytosEzcaTrustBundleResourceModel describes the resource data model.
+type KeytosEzcaTrustBundleResourceModel struct {
+	CertificatesPEM      types.List   `tfsdk:"certificates_pem"`
+	IncludeIntermediates types.Bool   `tfsdk:"include_intermediates"`
+	PKCS12Password       types.String `tfsdk:"pkcs12_password"`
+
+	BundlePEM                 types.String `tfsdk:"bundle_pem"`
+	CertificateThumbprintsHex types.List   `tfsdk:"certificate_thumbprints_hex"`
+	PKCS12Base64              types.String `tfsdk:"pkcs12_base64"`
+}
+
+func (r *KeytosEzcaTrustBundleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ezca_trust_bundle"
+}
+
+func (r *KeytosEzcaTrustBundleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Assembles the root and intermediate certificates of one or more EZCA authorities into a single trust bundle for distribution to fleets. The bundle is recomputed whenever its members change, for instance when an authority certificate in the `ca_chain_pem` of an issued certificate is renewed. Certificates are deduplicated and ordered, roots first, so that the bundle only changes when its members do.",
+
+		Attributes: map[string]schema.Attribute{
+			"certificates_pem": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Authority certificates in PEM format, each element holding one or more certificates, such as the elements of `ca_chain_pem` of `keytos_ezca_ssl_leaf_cert` resources. Certificates that are not certificate authorities are rejected.",
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"include_intermediates": schema.BoolAttribute{
+				MarkdownDescription: "When false, only the self-signed roots of `certificates_pem` are included in the bundle. Defaults to true.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"pkcs12_password": schema.StringAttribute{
+				MarkdownDescription: "Password protecting `pkcs12_base64`. Set it, possibly to an empty string, to get the bundle as a PKCS #12 trust store.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+
+			"bundle_pem": schema.StringAttribute{
+				MarkdownDescription: "Certificates of the bundle as concatenated PEM blocks.",
+				Computed:            true,
+			},
+			"certificate_thumbprints_hex": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Thumbprints of the certificates of the bundle, in the order of `bundle_pem`. These are SHA-1 sums of the raw certificate contents.",
+				Computed:            true,
+			},
+			"pkcs12_base64": schema.StringAttribute{
+				MarkdownDescription: "Certificates of the bundle as trusted certificate entries of a base64 encoded PKCS #12 trust store protected by `pkcs12_password`, which Java reads as a keystore and `keytool -importkeystore` converts to JKS. Null when `pkcs12_password` is not set.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (r *KeytosEzcaTrustBundleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data KeytosEzcaTrustBundleResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.assemble(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a trust bundle resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeytosEzcaTrustBundleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The bundle only depends on the configuration, so there is nothing to
+	// refresh
+}
+
+func (r *KeytosEzcaTrustBundleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data KeytosEzcaTrustBundleResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.assemble(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeytosEzcaTrustBundleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The bundle only exists in the state
+}
+
+func (r *KeytosEzcaTrustBundleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// The bundle is known in the plan when its members are, so that changes
+	// to it show in the plan. The PKCS #12 trust store is salted, so it is
+	// only encoded when applying.
+	var data KeytosEzcaTrustBundleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.CertificatesPEM.IsUnknown() || data.IncludeIntermediates.IsUnknown() {
+		return
+	}
+	for _, v := range data.CertificatesPEM.Elements() {
+		if v.IsUnknown() {
+			return
+		}
+	}
+	certs := trustBundleCertificates(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	bundlePEM, thumbprints := encodeTrustBundle(certs)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("bundle_pem"), bundlePEM)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificate_thumbprints_hex"), thumbprints)...)
+	if data.PKCS12Password.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("pkcs12_base64"), types.StringNull())...)
+	}
+}
+
+// assemble computes the bundle of the planned members into the model.
+func (r *KeytosEzcaTrustBundleResource) assemble(ctx context.Context, data *KeytosEzcaTrustBundleResourceModel, diags *diag.Diagnostics) {
+	certs := trustBundleCertificates(ctx, data, diags)
+	if diags.HasError() {
+		return
+	}
+	data.BundlePEM, data.CertificateThumbprintsHex = encodeTrustBundle(certs)
+	data.PKCS12Base64 = types.StringNull()
+	if !data.PKCS12Password.IsNull() {
+		der, err := encodePKCS12(nil, certs[0], certs[1:], data.PKCS12Password.ValueString())
+		if err != nil {
+			diags.AddError("Error Encoding PKCS #12 Trust Store", fmt.Sprintf("Error encoding the bundle as a PKCS #12 trust store: %v", err))
+			return
+		}
+		data.PKCS12Base64 = types.StringValue(base64.StdEncoding.EncodeToString(der))
+	}
+}
+
+// trustBundleCertificates parses the members of the bundle, reporting
+// invalid ones on their element of certificates_pem.
+func trustBundleCertificates(ctx context.Context, data *KeytosEzcaTrustBundleResourceModel, diags *diag.Diagnostics) []*x509.Certificate {
+	var pems []types.String
+	diags.Append(data.CertificatesPEM.ElementsAs(ctx, &pems, false)...)
+	if diags.HasError() {
+		return nil
+	}
+	var certs []*x509.Certificate
+	for i, s := range pems {
+		p := path.Root("certificates_pem").AtListIndex(i)
+		parsed, err := parseCertificatesPEM(s.ValueString())
+		if err != nil {
+			diags.AddAttributeError(p, "Invalid Certificate PEM", fmt.Sprintf("Error parsing certificate: %v", err))
+			continue
+		}
+		if len(parsed) == 0 {
+			diags.AddAttributeError(p, "Invalid Certificate PEM", "No certificate PEM block found.")
+			continue
+		}
+		for _, cert := range parsed {
+			if !cert.IsCA {
+				diags.AddAttributeError(p, "Invalid Trust Bundle Member", fmt.Sprintf("Certificate %s is not a certificate authority.", cert.Subject))
+			}
+		}
+		certs = append(certs, parsed...)
+	}
+	if diags.HasError() {
+		return nil
+	}
+	certs = trustBundle(certs, data.IncludeIntermediates.IsNull() || data.IncludeIntermediates.ValueBool())
+	if len(certs) == 0 {
+		diags.AddAttributeError(path.Root("include_intermediates"), "Empty Trust Bundle", "None of the certificates of certificates_pem is a self-signed root.")
+	}
+	return certs
+}
+
+// trustBundle deduplicates the certificates and orders them roots first, then
+// by subject and content, so that the bundle does not depend on the order of
+// its members. Intermediates are dropped unless includeIntermediates is set.
+func trustBundle(certs []*x509.Certificate, includeIntermediates bool) []*x509.Certificate {
+	seen := make(map[[sha1.Size]byte]bool, len(certs))
+	var bundle []*x509.Certificate
+	for _, cert := range certs {
+		thumb := sha1.Sum(cert.Raw)
+		if seen[thumb] || !includeIntermediates && !isSelfSigned(cert) {
+			continue
+		}
+		seen[thumb] = true
+		bundle = append(bundle, cert)
+	}
+	sort.SliceStable(bundle, func(i, j int) bool {
+		if ri, rj := isSelfSigned(bundle[i]), isSelfSigned(bundle[j]); ri != rj {
+			return ri
+		}
+		if si, sj := bundle[i].Subject.String(), bundle[j].Subject.String(); si != sj {
+			return si < sj
+		}
+		return bytes.Compare(bundle[i].Raw, bundle[j].Raw) < 0
+	})
+	return bundle
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawSubject, cert.RawIssuer)
+}
+
+// encodeTrustBundle encodes the bundle as concatenated PEM blocks along with
+// the thumbprints of its certificates.
+func encodeTrustBundle(certs []*x509.Certificate) (types.String, types.List) {
+	var sb strings.Builder
+	thumbprints := make([]attr.Value, len(certs))
+	for i, cert := range certs {
+		sb.WriteString(encodeCertificatePEM(cert, defaultPEMLineLength, false))
+		thumb := sha1.Sum(cert.Raw)
+		thumbprints[i] = types.StringValue(hex.EncodeToString(thumb[:]))
+	}
+	return types.StringValue(sb.String()), types.ListValueMust(types.StringType, thumbprints)
+}
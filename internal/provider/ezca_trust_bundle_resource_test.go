@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/markeytos/terraform-provider-keytos/internal/acctest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccKeytosEzcaTrustBundle(t *testing.T) {
+	rootKey, root := testSelfSignedCertificate(t, "Test Root")
+	_, intermediate := testIssuedCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "Test Intermediate"}, IsCA: true}, rootKey, root)
+	_, otherRoot := testSelfSignedCertificate(t, "Other Root")
+	_, leaf := testIssuedCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "Test Leaf"}}, rootKey, root)
+	pemOf := func(certs ...*x509.Certificate) string {
+		var sb strings.Builder
+		for _, cert := range certs {
+			sb.WriteString(encodeCertificatePEM(cert, defaultPEMLineLength, false))
+		}
+		return sb.String()
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccKeytosEzcaTrustBundleConfig("", pemOf(root), pemOf(leaf)),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Trust Bundle Member`),
+			},
+			// Create and Read testing
+			{
+				Config: testAccKeytosEzcaTrustBundleConfig("", pemOf(intermediate, root), pemOf(root)),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_trust_bundle.test",
+						tfjsonpath.New("bundle_pem"),
+						knownvalue.StringExact(pemOf(root, intermediate)),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_trust_bundle.test",
+						tfjsonpath.New("pkcs12_base64"),
+						knownvalue.Null(),
+					),
+				},
+			},
+			// Update testing
+			{
+				Config: testAccKeytosEzcaTrustBundleConfig("  include_intermediates = false\n  pkcs12_password = \"test\"", pemOf(intermediate, root), pemOf(otherRoot)),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_trust_bundle.test",
+						tfjsonpath.New("certificate_thumbprints_hex"),
+						knownvalue.ListSizeExact(2),
+					),
+					statecheck.ExpectKnownValue(
+						"keytos_ezca_trust_bundle.test",
+						tfjsonpath.New("pkcs12_base64"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccKeytosEzcaTrustBundleConfig(extra string, pems ...string) string {
+	var sb strings.Builder
+	for _, s := range pems {
+		fmt.Fprintf(&sb, "    %q,\n", s)
+	}
+	return fmt.Sprintf(`
+resource "keytos_ezca_trust_bundle" "test" {
+  certificates_pem = [
+%s  ]
+%s
+}
+`, sb.String(), extra)
+}
+
+func TestTrustBundle(t *testing.T) {
+	rootKey, root := testSelfSignedCertificate(t, "B Root")
+	_, intermediate := testIssuedCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "A Intermediate"}, IsCA: true}, rootKey, root)
+	_, otherRoot := testSelfSignedCertificate(t, "A Root")
+
+	bundle := trustBundle([]*x509.Certificate{intermediate, root, otherRoot, root}, true)
+	require.Equal(t, []*x509.Certificate{otherRoot, root, intermediate}, bundle)
+
+	bundle = trustBundle([]*x509.Certificate{intermediate, root}, false)
+	require.Equal(t, []*x509.Certificate{root}, bundle)
+}
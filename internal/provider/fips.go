@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+const fipsMinRSAKeySize = 2048
+
+// fipsCertificateRequestError returns why the certificate request would be
+// rejected when the provider runs in FIPS mode, or nil when it complies.
+func fipsCertificateRequestError(cr *x509.CertificateRequest) error {
+	switch pub := cr.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < fipsMinRSAKeySize {
+			return fmt.Errorf("RSA key size of %d bits is below the FIPS minimum of %d bits", pub.N.BitLen(), fipsMinRSAKeySize)
+		}
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+		default:
+			return fmt.Errorf("ECDSA curve %s is not FIPS approved", pub.Curve.Params().Name)
+		}
+	case ed25519.PublicKey:
+	default:
+		return fmt.Errorf("%s keys are not FIPS approved", cr.PublicKeyAlgorithm)
+	}
+
+	switch cr.SignatureAlgorithm {
+	case x509.SHA256WithRSA, x509.SHA384WithRSA, x509.SHA512WithRSA,
+		x509.SHA256WithRSAPSS, x509.SHA384WithRSAPSS, x509.SHA512WithRSAPSS,
+		x509.ECDSAWithSHA256, x509.ECDSAWithSHA384, x509.ECDSAWithSHA512,
+		x509.PureEd25519:
+	default:
+		return fmt.Errorf("signature algorithm %s is not FIPS approved", cr.SignatureAlgorithm)
+	}
+
+	return nil
+}
+
+// checkFIPSCertificateRequest adds an error diagnostic on the certificate
+// request attribute when the PEM encoded request is not FIPS compliant.
+// Requests that cannot be parsed are left to the regular validation.
+func checkFIPSCertificateRequest(csrPEM string, diags *diag.Diagnostics) {
+	der, err := csr(csrPEM)
+	if err != nil {
+		return
+	}
+	cr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return
+	}
+	if err := fipsCertificateRequestError(cr); err != nil {
+		diags.AddAttributeError(
+			path.Root("cert_request_pem"),
+			"Non-FIPS Certificate Request",
+			fmt.Sprintf("The provider is running with fips_mode enabled and the certificate request is not compliant: %v", err),
+		)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxTLSServerValidityDays is the maximum validity of publicly trusted TLS
+// server certificates set by the CA/Browser Forum baseline requirements.
+const maxTLSServerValidityDays = 398
+
+// defaultMaxValidityDays is the default maximum validity of the issuance
+// policy data source, a day under the CA/Browser Forum maximum so that
+// certificates comply however their last day is counted.
+const defaultMaxValidityDays = 397
+
+// defaultMinRSAKeySize is the smallest RSA key size allowed by the CA/Browser
+// Forum baseline requirements.
+const defaultMinRSAKeySize = 2048
+
+const (
+	policyRuleMaxValidity   = "max_validity"
+	policyRuleMinRSAKeySize = "min_rsa_key_size"
+	policyRuleNoSHA1        = "no_sha1"
+	policyRuleRequireSAN    = "require_subject_alternative_names"
+)
+
+// issuancePolicy is an organizational policy that certificates and the
+// authorities issuing them are checked against.
+type issuancePolicy struct {
+	// maxValidity is the longest validity period allowed, 0 for no limit.
+	maxValidity time.Duration
+	// minRSAKeySize is the smallest RSA key size in bits allowed, 0 for no
+	// limit.
+	minRSAKeySize int
+	forbidSHA1    bool
+	requireSAN    bool
+}
+
+var defaultIssuancePolicy = issuancePolicy{
+	maxValidity:   defaultMaxValidityDays * 24 * time.Hour,
+	minRSAKeySize: defaultMinRSAKeySize,
+	forbidSHA1:    true,
+	requireSAN:    true,
+}
+
+// policyViolation is a rule of an issuance policy that is not satisfied.
+type policyViolation struct {
+	rule    string
+	message string
+}
+
+// checkCertificate returns the violations of the policy by the certificate.
+func (p issuancePolicy) checkCertificate(cert *x509.Certificate) []policyViolation {
+	var vs []policyViolation
+	if validity := cert.NotAfter.Sub(cert.NotBefore); p.maxValidity > 0 && validity > p.maxValidity {
+		vs = append(vs, policyViolation{
+			rule:    policyRuleMaxValidity,
+			message: fmt.Sprintf("Certificate is valid for %s, longer than the maximum of %s.", formatDays(validity), formatDays(p.maxValidity)),
+		})
+	}
+	if pub, ok := cert.PublicKey.(*rsa.PublicKey); ok && pub.N.BitLen() < p.minRSAKeySize {
+		vs = append(vs, policyViolation{
+			rule:    policyRuleMinRSAKeySize,
+			message: fmt.Sprintf("Certificate has a %d bit RSA key, smaller than the minimum of %d bits.", pub.N.BitLen(), p.minRSAKeySize),
+		})
+	}
+	if p.forbidSHA1 && isSHA1SignatureAlgorithm(cert.SignatureAlgorithm) {
+		vs = append(vs, policyViolation{
+			rule:    policyRuleNoSHA1,
+			message: fmt.Sprintf("Certificate is signed with %s.", cert.SignatureAlgorithm),
+		})
+	}
+	if p.requireSAN && len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.IPAddresses)+len(cert.URIs) == 0 {
+		vs = append(vs, policyViolation{
+			rule:    policyRuleRequireSAN,
+			message: "Certificate has no subject alternative names.",
+		})
+	}
+	return vs
+}
+
+// checkAuthority returns the violations of the policy by an authority
+// signing with the hash algorithm, as reported by EZCA.
+func (p issuancePolicy) checkAuthority(hashAlgorithm string) []policyViolation {
+	var vs []policyViolation
+	if p.forbidSHA1 && strings.EqualFold(strings.ReplaceAll(hashAlgorithm, "-", ""), "SHA1") {
+		vs = append(vs, policyViolation{
+			rule:    policyRuleNoSHA1,
+			message: fmt.Sprintf("Authority signs with the %s hash algorithm.", hashAlgorithm),
+		})
+	}
+	return vs
+}
+
+// lintPolicy returns the policy issued certificates are linted against, the
+// default policy with the CA/Browser Forum validity limit only applying to
+// TLS server certificates.
+func lintPolicy(cert *x509.Certificate) issuancePolicy {
+	p := defaultIssuancePolicy
+	p.maxValidity = maxTLSServerValidityDays * 24 * time.Hour
+	if !slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageServerAuth) {
+		p.maxValidity = 0
+	}
+	return p
+}
+
+func isSHA1SignatureAlgorithm(a x509.SignatureAlgorithm) bool {
+	return a == x509.SHA1WithRSA || a == x509.ECDSAWithSHA1 || a == x509.DSAWithSHA1
+}
+
+func formatDays(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours()/24, 'f', -1, 64) + " days"
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssuancePolicyCheckCertificate(t *testing.T) {
+	notBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		cert  *x509.Certificate
+		rules []string
+	}{
+		{
+			name: "compliant",
+			cert: &x509.Certificate{
+				NotBefore:          notBefore,
+				NotAfter:           notBefore.Add(90 * 24 * time.Hour),
+				SignatureAlgorithm: x509.SHA256WithRSA,
+				DNSNames:           []string{"example.com"},
+			},
+		},
+		{
+			name: "too long",
+			cert: &x509.Certificate{
+				NotBefore:          notBefore,
+				NotAfter:           notBefore.Add(398 * 24 * time.Hour),
+				SignatureAlgorithm: x509.ECDSAWithSHA256,
+				DNSNames:           []string{"example.com"},
+			},
+			rules: []string{policyRuleMaxValidity},
+		},
+		{
+			name: "sha1 without sans",
+			cert: &x509.Certificate{
+				NotBefore:          notBefore,
+				NotAfter:           notBefore.Add(24 * time.Hour),
+				SignatureAlgorithm: x509.SHA1WithRSA,
+			},
+			rules: []string{policyRuleNoSHA1, policyRuleRequireSAN},
+		},
+		{
+			name: "small rsa key",
+			cert: &x509.Certificate{
+				NotBefore:          notBefore,
+				NotAfter:           notBefore.Add(24 * time.Hour),
+				SignatureAlgorithm: x509.SHA256WithRSA,
+				PublicKey:          &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), 1023), E: 65537},
+				DNSNames:           []string{"example.com"},
+			},
+			rules: []string{policyRuleMinRSAKeySize},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules []string
+			for _, v := range defaultIssuancePolicy.checkCertificate(tt.cert) {
+				rules = append(rules, v.rule)
+			}
+			require.Equal(t, tt.rules, rules)
+		})
+	}
+}
+
+func TestLintPolicy(t *testing.T) {
+	notBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{
+		NotBefore:          notBefore,
+		NotAfter:           notBefore.Add(2 * 365 * 24 * time.Hour),
+		SignatureAlgorithm: x509.SHA256WithRSA,
+		DNSNames:           []string{"example.com"},
+		ExtKeyUsage:        []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	require.Empty(t, lintPolicy(cert).checkCertificate(cert))
+
+	cert.ExtKeyUsage = append(cert.ExtKeyUsage, x509.ExtKeyUsageServerAuth)
+	vs := lintPolicy(cert).checkCertificate(cert)
+	require.Len(t, vs, 1)
+	require.Equal(t, policyRuleMaxValidity, vs[0].rule)
+
+	// The CA/Browser Forum maximum is allowed
+	cert.NotAfter = notBefore.Add(398 * 24 * time.Hour)
+	require.Empty(t, lintPolicy(cert).checkCertificate(cert))
+	cert.NotAfter = cert.NotAfter.Add(time.Second)
+	require.Len(t, lintPolicy(cert).checkCertificate(cert), 1)
+}
+
+func TestIssuancePolicyCheckAuthority(t *testing.T) {
+	require.Empty(t, defaultIssuancePolicy.checkAuthority("SHA256"))
+	require.Len(t, defaultIssuancePolicy.checkAuthority("SHA1"), 1)
+	require.Len(t, defaultIssuancePolicy.checkAuthority("sha-1"), 1)
+	require.Empty(t, issuancePolicy{}.checkAuthority("SHA1"))
+}
@@ -0,0 +1,183 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+)
+
+// keyVaultKeyID is an Azure Key Vault key identifier, such as
+// https://example.vault.azure.net/keys/name/version.
+type keyVaultKeyID struct {
+	vaultURL string
+	name     string
+	// version is empty for the current version of the key.
+	version string
+}
+
+func parseKeyVaultKeyID(s string) (keyVaultKeyID, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return keyVaultKeyID{}, err
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if u.Scheme != "https" || u.Host == "" || len(parts) < 2 || len(parts) > 3 || parts[0] != "keys" || parts[1] == "" {
+		return keyVaultKeyID{}, fmt.Errorf("expected a key identifier such as https://example.vault.azure.net/keys/name/version, got %q", s)
+	}
+	id := keyVaultKeyID{vaultURL: u.Scheme + "://" + u.Host, name: parts[1]}
+	if len(parts) == 3 {
+		id.version = parts[2]
+	}
+	return id, nil
+}
+
+var _ crypto.Signer = &keyVaultSigner{}
+
+// keyVaultSigner signs with an Azure Key Vault key, so that the private key
+// never leaves the vault.
+type keyVaultSigner struct {
+	ctx    context.Context
+	client *azkeys.Client
+	id     keyVaultKeyID
+	public crypto.PublicKey
+}
+
+// newKeyVaultSigner returns a signer for the Key Vault key, pinned to its
+// current version when the identifier has none. The context is used for the
+// sign operations.
+func newKeyVaultSigner(ctx context.Context, cred azcore.TokenCredential, keyID string) (*keyVaultSigner, error) {
+	id, err := parseKeyVaultKeyID(keyID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := azkeys.NewClient(id.vaultURL, cred, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.GetKey(ctx, id.name, id.version, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting key %s: %w", id.name, err)
+	}
+	if resp.Key == nil {
+		return nil, fmt.Errorf("key %s has no public key", id.name)
+	}
+	pub, err := jsonWebKeyPublicKey(resp.Key)
+	if err != nil {
+		return nil, err
+	}
+	if id.version == "" && resp.Key.KID != nil {
+		id.version = resp.Key.KID.Version()
+	}
+	return &keyVaultSigner{ctx: ctx, client: client, id: id, public: pub}, nil
+}
+
+func (s *keyVaultSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign signs the digest with the Key Vault key. RSA keys sign with PKCS #1
+// v1.5 and ECDSA signatures are returned ASN.1 encoded, as x509 expects.
+func (s *keyVaultSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	alg, err := keyVaultSignatureAlgorithm(s.public, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Sign(s.ctx, s.id.name, s.id.version, azkeys.SignParameters{Algorithm: &alg, Value: digest}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error signing with key %s: %w", s.id.name, err)
+	}
+	if _, ok := s.public.(*ecdsa.PublicKey); ok {
+		return ecdsaSignatureASN1(resp.Result)
+	}
+	return resp.Result, nil
+}
+
+// jsonWebKeyPublicKey returns the public key of a Key Vault RSA or elliptic
+// curve key.
+func jsonWebKeyPublicKey(k *azkeys.JSONWebKey) (crypto.PublicKey, error) {
+	if k.Kty == nil {
+		return nil, errors.New("key has no type")
+	}
+	switch *k.Kty {
+	case azkeys.KeyTypeRSA, azkeys.KeyTypeRSAHSM:
+		if len(k.N) == 0 || len(k.E) == 0 {
+			return nil, errors.New("RSA key has no modulus or exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(k.N), E: int(new(big.Int).SetBytes(k.E).Int64())}, nil
+	case azkeys.KeyTypeEC, azkeys.KeyTypeECHSM:
+		var curve elliptic.Curve
+		switch {
+		case k.Crv == nil:
+			return nil, errors.New("elliptic curve key has no curve")
+		case *k.Crv == azkeys.CurveNameP256:
+			curve = elliptic.P256()
+		case *k.Crv == azkeys.CurveNameP384:
+			curve = elliptic.P384()
+		case *k.Crv == azkeys.CurveNameP521:
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported elliptic curve %s", *k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(k.X), Y: new(big.Int).SetBytes(k.Y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", *k.Kty)
+	}
+}
+
+// keyVaultSignatureAlgorithm returns the Key Vault algorithm signing digests
+// of the hash of opts with the key.
+func keyVaultSignatureAlgorithm(pub crypto.PublicKey, opts crypto.SignerOpts) (azkeys.SignatureAlgorithm, error) {
+	if _, ok := opts.(*rsa.PSSOptions); ok {
+		return "", errors.New("RSA-PSS signatures are not supported")
+	}
+	var algs map[crypto.Hash]azkeys.SignatureAlgorithm
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		algs = map[crypto.Hash]azkeys.SignatureAlgorithm{
+			crypto.SHA256: azkeys.SignatureAlgorithmRS256,
+			crypto.SHA384: azkeys.SignatureAlgorithmRS384,
+			crypto.SHA512: azkeys.SignatureAlgorithmRS512,
+		}
+	case *ecdsa.PublicKey:
+		algs = map[crypto.Hash]azkeys.SignatureAlgorithm{
+			crypto.SHA256: azkeys.SignatureAlgorithmES256,
+			crypto.SHA384: azkeys.SignatureAlgorithmES384,
+			crypto.SHA512: azkeys.SignatureAlgorithmES512,
+		}
+	default:
+		return "", fmt.Errorf("unsupported public key type %T", pub)
+	}
+	alg, ok := algs[opts.HashFunc()]
+	if !ok {
+		return "", fmt.Errorf("unsupported hash function %s", opts.HashFunc())
+	}
+	return alg, nil
+}
+
+// ecdsaSignatureASN1 converts an ECDSA signature from the r || s format of
+// Key Vault to ASN.1.
+func ecdsaSignatureASN1(sig []byte) ([]byte, error) {
+	if len(sig) == 0 || len(sig)%2 != 0 {
+		return nil, fmt.Errorf("invalid ECDSA signature length %d", len(sig))
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{
+		R: new(big.Int).SetBytes(sig[:len(sig)/2]),
+		S: new(big.Int).SetBytes(sig[len(sig)/2:]),
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyVaultKeyID(t *testing.T) {
+	id, err := parseKeyVaultKeyID("https://example.vault.azure.net/keys/test/0123456789abcdef")
+	require.NoError(t, err)
+	require.Equal(t, keyVaultKeyID{vaultURL: "https://example.vault.azure.net", name: "test", version: "0123456789abcdef"}, id)
+
+	id, err = parseKeyVaultKeyID("https://example.vault.azure.net/keys/test")
+	require.NoError(t, err)
+	require.Equal(t, keyVaultKeyID{vaultURL: "https://example.vault.azure.net", name: "test"}, id)
+
+	for _, s := range []string{
+		"http://example.vault.azure.net/keys/test",
+		"https://example.vault.azure.net/keys",
+		"https://example.vault.azure.net/secrets/test",
+		"https://example.vault.azure.net/keys/test/version/extra",
+	} {
+		_, err := parseKeyVaultKeyID(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestJSONWebKeyPublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pub, err := jsonWebKeyPublicKey(&azkeys.JSONWebKey{
+		Kty: to.Ptr(azkeys.KeyTypeRSAHSM),
+		N:   rsaKey.N.Bytes(),
+		E:   big.NewInt(int64(rsaKey.E)).Bytes(),
+	})
+	require.NoError(t, err)
+	require.True(t, rsaKey.PublicKey.Equal(pub))
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	pub, err = jsonWebKeyPublicKey(&azkeys.JSONWebKey{
+		Kty: to.Ptr(azkeys.KeyTypeEC),
+		Crv: to.Ptr(azkeys.CurveNameP384),
+		X:   ecKey.X.Bytes(),
+		Y:   ecKey.Y.Bytes(),
+	})
+	require.NoError(t, err)
+	require.True(t, ecKey.PublicKey.Equal(pub))
+
+	_, err = jsonWebKeyPublicKey(&azkeys.JSONWebKey{Kty: to.Ptr(azkeys.KeyTypeOct)})
+	require.Error(t, err)
+}
+
+func TestKeyVaultSignatureAlgorithm(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	alg, err := keyVaultSignatureAlgorithm(&rsaKey.PublicKey, crypto.SHA384)
+	require.NoError(t, err)
+	require.Equal(t, azkeys.SignatureAlgorithmRS384, alg)
+	alg, err = keyVaultSignatureAlgorithm(&ecKey.PublicKey, crypto.SHA256)
+	require.NoError(t, err)
+	require.Equal(t, azkeys.SignatureAlgorithmES256, alg)
+
+	_, err = keyVaultSignatureAlgorithm(&rsaKey.PublicKey, &rsa.PSSOptions{Hash: crypto.SHA256})
+	require.Error(t, err)
+	_, err = keyVaultSignatureAlgorithm(&ecKey.PublicKey, crypto.SHA1)
+	require.Error(t, err)
+}
+
+func TestECDSASignatureASN1(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte("test"))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	// Key Vault returns r and s as fixed size big-endian integers
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	der, err := ecdsaSignatureASN1(sig)
+	require.NoError(t, err)
+	require.True(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], der))
+
+	_, err = ecdsaSignatureASN1(sig[:63])
+	require.Error(t, err)
+}
@@ -8,17 +8,39 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/metaschema"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/markeytos/ezca-go"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 const defaultEzcaURL = "portal.ezca.io"
 
+// defaultSourceTag identifies requests made by the provider in EZCA.
+const defaultSourceTag = "keytos terraform provider"
+
+const (
+	authMethodDefault     = "default"
+	authMethodDeviceCode  = "devicecode"
+	authMethodInteractive = "interactive"
+)
+
+const (
+	refreshFailureModeError            = "error"
+	refreshFailureModeWarnAndKeepState = "warn_and_keep_state"
+)
+
 // KeytosProvider defines the provider implementation.
 type KeytosProvider struct {
 	// version is set to the provider version on release, "dev" when the
@@ -29,7 +51,31 @@ type KeytosProvider struct {
 
 // KeytosProviderModel describes the provider data model.
 type KeytosProviderModel struct {
-	EZCAUrl types.String `tfsdk:"ezca_url"`
+	EZCAUrl          types.String `tfsdk:"ezca_url"`
+	EZCAFallbackURLs types.List   `tfsdk:"ezca_fallback_urls"`
+	AuthMethod       types.String `tfsdk:"auth_method"`
+	FIPSMode         types.Bool   `tfsdk:"fips_mode"`
+	Retry            types.Object `tfsdk:"retry"`
+	RequestTimeout   types.String `tfsdk:"request_timeout"`
+
+	RefreshFailureMode types.String `tfsdk:"refresh_failure_mode"`
+}
+
+// KeytosProviderMetaModel describes the provider_meta data model, set by the
+// modules using the provider.
+type KeytosProviderMetaModel struct {
+	ModuleName    types.String `tfsdk:"module_name"`
+	ModuleVersion types.String `tfsdk:"module_version"`
+}
+
+// KeytosData is shared with data sources and resources when the provider is
+// configured.
+type KeytosData struct {
+	Client             *ezcaClient
+	Credential         azcore.TokenCredential
+	FIPSMode           bool
+	RefreshFailureMode string
+	Revocation         *revocationChecker
 }
 
 func (p *KeytosProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -39,11 +85,82 @@ func (p *KeytosProvider) Metadata(ctx context.Context, req provider.MetadataRequ
 
 func (p *KeytosProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages certificates issued by Keytos EZCA. Modules can identify the certificates they request in EZCA by setting `module_name` and `module_version` in a `provider_meta \"keytos\"` block of their `terraform` block, which are appended to the source tag of the requests.",
 		Attributes: map[string]schema.Attribute{
 			"ezca_url": schema.StringAttribute{
 				MarkdownDescription: "EZCA instance URL",
 				Optional:            true,
 			},
+			"ezca_fallback_urls": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "EZCA instance URLs to fail over to, in order, when `ezca_url` cannot be reached. Failover only happens on connection errors and invalid server responses, such as a regional outage. Signing requests only fail over when they could not be sent, so that a request the instance may have processed does not issue a duplicate certificate.",
+				Optional:            true,
+			},
+			"auth_method": schema.StringAttribute{
+				MarkdownDescription: "Azure authentication method. One of `default` (environment, workload identity, managed identity or Azure CLI credentials), `devicecode` or `interactive` (browser sign-in). Defaults to `default`. The device code sign-in instructions are written to the provider logs at the `WARN` level, so run Terraform with `TF_LOG_PROVIDER=WARN` to see them.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(authMethodDefault, authMethodDeviceCode, authMethodInteractive),
+				},
+			},
+			"fips_mode": schema.BoolAttribute{
+				MarkdownDescription: "When true, plans requesting non-FIPS key types, key sizes or hash algorithms are rejected. Only RSA keys of at least 2048 bits, ECDSA keys on the P-256, P-384 and P-521 curves and Ed25519 keys signed with SHA-2 family hashes are accepted.",
+				Optional:            true,
+			},
+			"retry": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf("Number of times a request is attempted. Set to 1 to not retry. Defaults to %d.", defaultRetryMaxAttempts),
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"interval": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Time to wait after the first attempt, as a Go duration string. Defaults to `%s`.", defaultRetryInterval),
+						Optional:            true,
+					},
+					"backoff_multiplier": schema.Float64Attribute{
+						MarkdownDescription: fmt.Sprintf("Factor the time to wait grows by after every attempt. Set to 1 to wait `interval` between all attempts. Defaults to %d.", defaultRetryBackoffMultiplier),
+						Optional:            true,
+						Validators: []validator.Float64{
+							float64validator.AtLeast(1),
+						},
+					},
+					"max_interval": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Maximum time to wait between attempts, as a Go duration string. Defaults to `%s`.", defaultRetryMaxInterval),
+						Optional:            true,
+					},
+				},
+				MarkdownDescription: "Retries of requests failing with transient errors: `ezca_url` and all of `ezca_fallback_urls` being unreachable or answering with gateway errors, requests timing out, or connections dropping. Errors returned by EZCA, such as permission or validation errors, are never retried. Signing requests are only retried when they could not be sent, so that a lost response does not issue the certificate twice. Resources can override these settings.",
+				Optional:            true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Time limit of a request to a single EZCA instance, as a Go duration string. A request that times out fails over to the next instance or is retried, except signing requests, which EZCA may already have processed. Defaults to no limit. Resources can override this setting.",
+				Optional:            true,
+			},
+			"refresh_failure_mode": schema.StringAttribute{
+				MarkdownDescription: "What to do when EZCA cannot be reached while refreshing a resource. One of `error` (fail the refresh) or `warn_and_keep_state` (warn and keep the resource as stored in the state, so that an outage does not block plans that only reference certificates). Errors returned by EZCA always fail the refresh. Defaults to `error`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(refreshFailureModeError, refreshFailureModeWarnAndKeepState),
+				},
+			},
+		},
+	}
+}
+
+func (p *KeytosProvider) MetaSchema(ctx context.Context, req provider.MetaSchemaRequest, resp *provider.MetaSchemaResponse) {
+	resp.Schema = metaschema.Schema{
+		Attributes: map[string]metaschema.Attribute{
+			"module_name": metaschema.StringAttribute{
+				MarkdownDescription: "Name of the module using the provider, appended to the source tag of the certificates it requests so EZCA administrators can trace them back to it.",
+				Optional:            true,
+			},
+			"module_version": metaschema.StringAttribute{
+				MarkdownDescription: "Version of the module using the provider, appended to the source tag along with `module_name`.",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -60,31 +177,93 @@ func (p *KeytosProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	if ezcaURL == "" {
 		ezcaURL = defaultEzcaURL
 	}
+	urls := []string{ezcaURL}
+	if !data.EZCAFallbackURLs.IsNull() {
+		var fallbackURLs []string
+		resp.Diagnostics.Append(data.EZCAFallbackURLs.ElementsAs(ctx, &fallbackURLs, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		urls = append(urls, fallbackURLs...)
+	}
+
+	policy, err := defaultRetryPolicy.override(ctx, data.Retry, data.RequestTimeout)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Retry Settings", fmt.Sprintf("Invalid retry settings: %v", err))
+		return
+	}
+
+	authMethod := data.AuthMethod.ValueString()
+	if authMethod == authMethodDeviceCode {
+		resp.Diagnostics.AddWarning(
+			"Device Code Authentication",
+			"Device code sign-in instructions are written to the provider logs. Run Terraform with TF_LOG_PROVIDER=WARN to see them.",
+		)
+	}
 
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	cred, err := newCredential(authMethod)
 	if err != nil {
 		resp.Diagnostics.AddError("Could not get azure credential", fmt.Sprintf("Could not get Azure credential: %v", err))
 		return
 	}
-	c, err := ezca.NewClient(ezcaURL, cred)
+	c, err := newEzcaClient(urls, cred, policy)
 	if err != nil {
 		resp.Diagnostics.AddError("Could not initialize EZCA client", fmt.Sprintf("EZCA Client initialization error: %v", err))
 		return
 	}
 
-	resp.DataSourceData = c
-	resp.ResourceData = c
+	refreshFailureMode := data.RefreshFailureMode.ValueString()
+	if refreshFailureMode == "" {
+		refreshFailureMode = refreshFailureModeError
+	}
+
+	kd := &KeytosData{
+		Client:             c,
+		Credential:         cred,
+		FIPSMode:           data.FIPSMode.ValueBool(),
+		RefreshFailureMode: refreshFailureMode,
+		Revocation:         newRevocationChecker(),
+	}
+	resp.DataSourceData = kd
+	resp.ResourceData = kd
+}
+
+func newCredential(authMethod string) (azcore.TokenCredential, error) {
+	switch authMethod {
+	case authMethodDeviceCode:
+		return azidentity.NewDeviceCodeCredential(&azidentity.DeviceCodeCredentialOptions{
+			UserPrompt: func(ctx context.Context, m azidentity.DeviceCodeMessage) error {
+				tflog.Warn(ctx, m.Message)
+				return nil
+			},
+		})
+	case authMethodInteractive:
+		return azidentity.NewInteractiveBrowserCredential(nil)
+	default:
+		return azidentity.NewDefaultAzureCredential(nil)
+	}
 }
 
 func (p *KeytosProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewKeytosEzcaSslLeafCertResource,
+		NewKeytosEzcaSslCertResource,
+		NewKeytosEzcaCertPairResource,
+		NewKeytosEzcaSslLeafCertSetResource,
+		NewKeytosEzcaCodeSigningCertResource,
+		NewKeytosEzcaRadiusServerCertResource,
+		NewKeytosEzcaTimestampingCertResource,
+		NewKeytosEzcaOcspResponderCertResource,
+		NewKeytosEzcaTrustBundleResource,
 	}
 }
 
 func (p *KeytosProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewKeytosEzcaSslAuthorityDataSource,
+		NewKeytosEzcaPermissionCheckDataSource,
+		NewKeytosEzcaIssuancePolicyDataSource,
+		NewKeytosEzcaRenewalForecastDataSource,
 	}
 }
 
@@ -96,5 +275,24 @@ func New(version string) func() provider.Provider {
 	}
 }
 
+// sourceTag returns the source tag of the requests made for the module
+// described by the provider_meta block.
+func sourceTag(ctx context.Context, providerMeta tfsdk.Config, diags *diag.Diagnostics) string {
+	if providerMeta.Raw.IsNull() {
+		return defaultSourceTag
+	}
+	var meta KeytosProviderMetaModel
+	diags.Append(providerMeta.Get(ctx, &meta)...)
+	if meta.ModuleName.ValueString() == "" {
+		return defaultSourceTag
+	}
+	module := meta.ModuleName.ValueString()
+	if meta.ModuleVersion.ValueString() != "" {
+		module += "@" + meta.ModuleVersion.ValueString()
+	}
+	return defaultSourceTag + " (module " + module + ")"
+}
+
 // Ensure KeytosProvider satisfies provider interface.
 var _ provider.Provider = &KeytosProvider{}
+var _ provider.ProviderWithMetaSchema = &KeytosProvider{}
@@ -4,8 +4,15 @@
 package provider
 
 import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/markeytos/terraform-provider-keytos/internal/acctest"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -14,3 +21,42 @@ const (
 )
 
 var ProtoV6ProviderFactories = acctest.ProtoV6ProviderFactories(map[string]func() provider.Provider{"keytos": New("test")})
+
+func TestSourceTag(t *testing.T) {
+	ctx := context.Background()
+	var schemaResp provider.MetaSchemaResponse
+	(&KeytosProvider{}).MetaSchema(ctx, provider.MetaSchemaRequest{}, &schemaResp)
+	metaType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	tests := []struct {
+		name string
+		raw  tftypes.Value
+		want string
+	}{
+		{name: "no provider_meta", raw: tftypes.NewValue(metaType, nil), want: "keytos terraform provider"},
+		{
+			name: "module name",
+			raw: tftypes.NewValue(metaType, map[string]tftypes.Value{
+				"module_name":    tftypes.NewValue(tftypes.String, "network"),
+				"module_version": tftypes.NewValue(tftypes.String, nil),
+			}),
+			want: "keytos terraform provider (module network)",
+		},
+		{
+			name: "module name and version",
+			raw: tftypes.NewValue(metaType, map[string]tftypes.Value{
+				"module_name":    tftypes.NewValue(tftypes.String, "network"),
+				"module_version": tftypes.NewValue(tftypes.String, "1.2.0"),
+			}),
+			want: "keytos terraform provider (module network@1.2.0)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			got := sourceTag(ctx, tfsdk.Config{Schema: schemaResp.Schema, Raw: tt.raw}, &diags)
+			require.False(t, diags.HasError())
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
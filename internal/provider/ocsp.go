@@ -0,0 +1,248 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ocsp"
+)
+
+// defaultRevocationBatchWindow is how long revocation checks of certificates
+// of the same issuer are collected before being sent. Terraform refreshes
+// resources concurrently, so checks of a single refresh arrive together.
+const defaultRevocationBatchWindow = 100 * time.Millisecond
+
+// defaultOCSPWaitTimeout and defaultOCSPWaitInterval are how long and how
+// often the OCSP responder is polled for newly issued certificates.
+const (
+	defaultOCSPWaitTimeout  = 5 * time.Minute
+	defaultOCSPWaitInterval = 5 * time.Second
+)
+
+// maxOCSPResponseSize bounds the size of the OCSP responses read.
+const maxOCSPResponseSize = 1 << 20
+
+var errNoOCSPResponder = errors.New("certificate has no OCSP responder")
+
+var oidSHA1 = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+
+// revocationChecker checks the revocation status of certificates with the
+// OCSP responders of their issuers. It lives for a provider run: checks of
+// certificates of the same issuer made within the batch window are sent to
+// the responder in a single request, and results are cached so that every
+// certificate is checked at most once per run.
+type revocationChecker struct {
+	client *http.Client
+	window time.Duration
+
+	mu       sync.Mutex
+	statuses map[string]*revocationStatus
+	batches  map[string]*revocationBatch
+}
+
+// revocationStatus is the result of a revocation check, set once done is
+// closed.
+type revocationStatus struct {
+	done      chan struct{}
+	revoked   bool
+	revokedAt time.Time
+	err       error
+}
+
+// revocationBatch holds the pending checks of certificates of an issuer.
+type revocationBatch struct {
+	responder string
+	issuer    *x509.Certificate
+	certs     []*x509.Certificate
+	statuses  []*revocationStatus
+}
+
+func newRevocationChecker() *revocationChecker {
+	return &revocationChecker{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		window:   defaultRevocationBatchWindow,
+		statuses: map[string]*revocationStatus{},
+		batches:  map[string]*revocationBatch{},
+	}
+}
+
+// check returns whether the certificate issued by issuer is revoked and
+// when, as reported by the OCSP responder of the certificate.
+func (c *revocationChecker) check(ctx context.Context, cert, issuer *x509.Certificate) (bool, time.Time, error) {
+	if len(cert.OCSPServer) == 0 {
+		return false, time.Time{}, errNoOCSPResponder
+	}
+	responder := cert.OCSPServer[0]
+	issuerHash := sha1.Sum(issuer.Raw)
+	batchKey := responder + " " + hex.EncodeToString(issuerHash[:])
+	key := batchKey + " " + cert.SerialNumber.String()
+
+	c.mu.Lock()
+	s, ok := c.statuses[key]
+	if !ok {
+		s = &revocationStatus{done: make(chan struct{})}
+		c.statuses[key] = s
+		b, ok := c.batches[batchKey]
+		if !ok {
+			b = &revocationBatch{responder: responder, issuer: issuer}
+			c.batches[batchKey] = b
+			time.AfterFunc(c.window, func() {
+				c.mu.Lock()
+				delete(c.batches, batchKey)
+				c.mu.Unlock()
+				c.send(b)
+			})
+		}
+		b.certs = append(b.certs, cert)
+		b.statuses = append(b.statuses, s)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return s.revoked, s.revokedAt, s.err
+	case <-ctx.Done():
+		return false, time.Time{}, ctx.Err()
+	}
+}
+
+// send queries the status of the certificates of the batch, falling back to
+// a request per certificate when the responder does not answer for all of
+// them at once, as RFC 5019 responders do.
+func (c *revocationChecker) send(b *revocationBatch) {
+	der, err := c.query(b.responder, b.issuer, b.certs)
+	for i, cert := range b.certs {
+		s := b.statuses[i]
+		var r *ocsp.Response
+		if err == nil {
+			r, s.err = ocsp.ParseResponseForCert(der, cert, b.issuer)
+		} else {
+			s.err = err
+		}
+		if s.err != nil && len(b.certs) > 1 {
+			var single []byte
+			single, s.err = c.query(b.responder, b.issuer, []*x509.Certificate{cert})
+			if s.err == nil {
+				r, s.err = ocsp.ParseResponseForCert(single, cert, b.issuer)
+			}
+		}
+		if s.err == nil {
+			s.revoked = r.Status == ocsp.Revoked
+			s.revokedAt = r.RevokedAt
+		}
+		close(s.done)
+	}
+}
+
+// waitUntilKnown polls the OCSP responder of the certificate until it
+// reports a status for it, as responders may only learn about certificates
+// some time after they are issued. It returns the last error when ctx is
+// done first.
+func (c *revocationChecker) waitUntilKnown(ctx context.Context, cert, issuer *x509.Certificate, interval time.Duration) error {
+	if len(cert.OCSPServer) == 0 {
+		return errNoOCSPResponder
+	}
+	for {
+		der, err := c.query(cert.OCSPServer[0], issuer, []*x509.Certificate{cert})
+		if err == nil {
+			var r *ocsp.Response
+			r, err = ocsp.ParseResponseForCert(der, cert, issuer)
+			if err == nil && r.Status != ocsp.Unknown {
+				return nil
+			}
+			if err == nil {
+				err = errors.New("OCSP responder reported an unknown status")
+			}
+		}
+		tflog.Debug(ctx, "certificate not yet known to the OCSP responder", map[string]any{"serial_number": cert.SerialNumber.String(), "error": err.Error()})
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// query posts an OCSP request for the certificates to the responder and
+// returns the response.
+func (c *revocationChecker) query(responder string, issuer *x509.Certificate, certs []*x509.Certificate) ([]byte, error) {
+	req, err := createOCSPRequest(issuer, certs)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Post(responder, "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP responder %s returned status %s", responder, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseSize))
+}
+
+type ocspCertID struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	NameHash      []byte
+	IssuerKeyHash []byte
+	SerialNumber  *big.Int
+}
+
+type ocspRequestEntry struct {
+	Cert ocspCertID
+}
+
+type ocspTBSRequest struct {
+	Version     int `asn1:"explicit,tag:0,default:0,optional"`
+	RequestList []ocspRequestEntry
+}
+
+type ocspRequest struct {
+	TBSRequest ocspTBSRequest
+}
+
+// createOCSPRequest returns a DER encoded OCSP request for the certificates
+// issued by issuer. Unlike ocsp.CreateRequest, it supports requesting the
+// status of several certificates at once as allowed by RFC 6960.
+func createOCSPRequest(issuer *x509.Certificate, certs []*x509.Certificate) ([]byte, error) {
+	var publicKeyInfo struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &publicKeyInfo); err != nil {
+		return nil, err
+	}
+	nameHash := sha1.Sum(issuer.RawSubject)
+	keyHash := sha1.Sum(publicKeyInfo.PublicKey.RightAlign())
+
+	var req ocspRequest
+	for _, cert := range certs {
+		req.TBSRequest.RequestList = append(req.TBSRequest.RequestList, ocspRequestEntry{
+			Cert: ocspCertID{
+				HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA1, Parameters: asn1.NullRawValue},
+				NameHash:      nameHash[:],
+				IssuerKeyHash: keyHash[:],
+				SerialNumber:  cert.SerialNumber,
+			},
+		})
+	}
+	return asn1.Marshal(req)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestRevocationChecker(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+	revokedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	// The responder answers for the first certificate of a request only,
+	// as RFC 5019 responders do
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		req, err := ocsp.ParseRequest(body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		tmpl := ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now(),
+		}
+		if req.SerialNumber.Int64() == 3 {
+			tmpl.Status = ocsp.Revoked
+			tmpl.RevokedAt = revokedAt
+		}
+		resp, err := ocsp.CreateResponse(ca, ca, tmpl, caKey)
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, _ = w.Write(resp)
+	}))
+	defer srv.Close()
+
+	var certs []*x509.Certificate
+	for i := int64(2); i <= 4; i++ {
+		certs = append(certs, testOCSPLeafCertificate(t, ca, caKey, i, srv.URL))
+	}
+
+	c := newRevocationChecker()
+	check := func() {
+		var wg sync.WaitGroup
+		for _, cert := range certs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				revoked, at, err := c.check(context.Background(), cert, ca)
+				assert.NoError(t, err)
+				assert.Equal(t, cert.SerialNumber.Int64() == 3, revoked)
+				if revoked {
+					assert.True(t, revokedAt.Equal(at))
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	// A batch request, then a request for each certificate the responder
+	// did not answer for
+	check()
+	require.EqualValues(t, 3, requests.Load())
+
+	// Cached for the run
+	check()
+	require.EqualValues(t, 3, requests.Load())
+
+	_, ca2 := testSelfSignedCertificate(t, "Other CA")
+	_, _, err := c.check(context.Background(), ca2, ca2)
+	require.ErrorIs(t, err, errNoOCSPResponder)
+}
+
+func TestRevocationCheckerWaitUntilKnown(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+
+	// The responder learns about the certificate on the third request
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := ocsp.Unknown
+		if requests.Add(1) >= 3 {
+			status = ocsp.Good
+		}
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		req, err := ocsp.ParseRequest(body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		resp, err := ocsp.CreateResponse(ca, ca, ocsp.Response{Status: status, SerialNumber: req.SerialNumber, ThisUpdate: time.Now()}, caKey)
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, _ = w.Write(resp)
+	}))
+	defer srv.Close()
+	cert := testOCSPLeafCertificate(t, ca, caKey, 2, srv.URL)
+
+	c := newRevocationChecker()
+	require.NoError(t, c.waitUntilKnown(context.Background(), cert, ca, time.Millisecond))
+	require.EqualValues(t, 3, requests.Load())
+
+	requests.Store(-100)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := c.waitUntilKnown(ctx, cert, ca, time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "unknown status")
+
+	_, ca2 := testSelfSignedCertificate(t, "Other CA")
+	require.ErrorIs(t, c.waitUntilKnown(context.Background(), ca2, ca2, time.Millisecond), errNoOCSPResponder)
+}
+
+func TestCreateOCSPRequest(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+	certs := []*x509.Certificate{
+		testOCSPLeafCertificate(t, ca, caKey, 2, "http://ocsp.example.com"),
+		testOCSPLeafCertificate(t, ca, caKey, 3, "http://ocsp.example.com"),
+	}
+
+	der, err := createOCSPRequest(ca, certs)
+	require.NoError(t, err)
+	var req ocspRequest
+	rest, err := asn1.Unmarshal(der, &req)
+	require.NoError(t, err)
+	require.Empty(t, rest)
+	require.Len(t, req.TBSRequest.RequestList, 2)
+
+	// The certificate identifiers match those of the reference implementation
+	single, err := ocsp.CreateRequest(certs[1], ca, nil)
+	require.NoError(t, err)
+	parsed, err := ocsp.ParseRequest(single)
+	require.NoError(t, err)
+	id := req.TBSRequest.RequestList[1].Cert
+	require.Equal(t, parsed.IssuerNameHash, id.NameHash)
+	require.Equal(t, parsed.IssuerKeyHash, id.IssuerKeyHash)
+	require.Equal(t, parsed.SerialNumber, id.SerialNumber)
+}
+
+func testOCSPLeafCertificate(t *testing.T, ca *x509.Certificate, caKey crypto.Signer, serial int64, responder string) *x509.Certificate {
+	t.Helper()
+	_, cert := testIssuedCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		OCSPServer:   []string{responder},
+	}, caKey, ca)
+	return cert
+}
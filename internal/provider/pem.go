@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"strings"
+)
+
+const defaultPEMLineLength = 64
+
+// encodeCertificatePEM encodes the certificate as a PEM block with the
+// base64 body wrapped every lineLength characters. When explanatoryText is
+// set, the subject and issuer of the certificate are written before the
+// encapsulation boundary as described in RFC 7468 section 5.2.
+func encodeCertificatePEM(cert *x509.Certificate, lineLength int, explanatoryText bool) string {
+	if lineLength <= 0 {
+		lineLength = defaultPEMLineLength
+	}
+
+	var sb strings.Builder
+	if explanatoryText {
+		sb.WriteString("subject=" + cert.Subject.String() + "\n")
+		sb.WriteString("issuer=" + cert.Issuer.String() + "\n")
+	}
+	sb.WriteString("-----BEGIN CERTIFICATE-----\n")
+	body := base64.StdEncoding.EncodeToString(cert.Raw)
+	for len(body) > lineLength {
+		sb.WriteString(body[:lineLength] + "\n")
+		body = body[lineLength:]
+	}
+	if len(body) > 0 {
+		sb.WriteString(body + "\n")
+	}
+	sb.WriteString("-----END CERTIFICATE-----\n")
+	return sb.String()
+}
+
+// parseCertificatePEM parses the first certificate PEM block of s, skipping
+// any explanatory text.
+func parseCertificatePEM(s string) (*x509.Certificate, error) {
+	b, _ := pem.Decode([]byte(s))
+	if b == nil {
+		return nil, errors.New("no valid PEM block found as certificate")
+	}
+	if b.Type != "CERTIFICATE" {
+		return nil, errors.New("PEM block is not of certificate type")
+	}
+	return x509.ParseCertificate(b.Bytes)
+}
+
+// encodeDebianCACertificates encodes the authority chain as a drop-in for
+// the Debian ca-certificates package, each certificate preceded by a comment
+// naming it.
+func encodeDebianCACertificates(chain []*x509.Certificate) string {
+	var sb strings.Builder
+	for _, cert := range chain {
+		sb.WriteString("# " + cert.Subject.String() + "\n")
+		sb.WriteString(encodeCertificatePEM(cert, defaultPEMLineLength, false))
+	}
+	return sb.String()
+}
+
+// encodeRHELAnchor encodes the authority chain as an anchor for the RHEL
+// ca-certificates package, in the OpenSSL format with explanatory text that
+// p11-kit reads.
+func encodeRHELAnchor(chain []*x509.Certificate) string {
+	var sb strings.Builder
+	for _, cert := range chain {
+		sb.WriteString(encodeCertificatePEM(cert, defaultPEMLineLength, true))
+	}
+	return sb.String()
+}
+
+// encodeMacOSPEM encodes the authority chain as bare concatenated PEM blocks,
+// which is the only text format the macOS security tool imports.
+func encodeMacOSPEM(chain []*x509.Certificate) string {
+	var sb strings.Builder
+	for _, cert := range chain {
+		sb.WriteString(encodeCertificatePEM(cert, defaultPEMLineLength, false))
+	}
+	return sb.String()
+}
+
+// encodeFullChainPEM encodes the certificate followed by the intermediate
+// authorities of its chain, as web servers expect. Self-signed roots are left
+// out as clients must already trust them.
+func encodeFullChainPEM(cert *x509.Certificate, chain []*x509.Certificate, lineLength int, explanatoryText bool) string {
+	var sb strings.Builder
+	sb.WriteString(encodeCertificatePEM(cert, lineLength, explanatoryText))
+	for _, c := range chain {
+		if bytes.Equal(c.RawSubject, c.RawIssuer) {
+			continue
+		}
+		sb.WriteString(encodeCertificatePEM(c, lineLength, explanatoryText))
+	}
+	return sb.String()
+}
+
+// parseCertificatesPEM parses all the certificate PEM blocks of s, skipping
+// any explanatory text.
+func parseCertificatesPEM(s string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(s)
+	for {
+		var b *pem.Block
+		b, rest = pem.Decode(rest)
+		if b == nil {
+			return certs, nil
+		}
+		if b.Type != "CERTIFICATE" {
+			return nil, errors.New("PEM block is not of certificate type")
+		}
+		cert, err := x509.ParseCertificate(b.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto"
+	"crypto/x509"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// encodePKCS12 packages the certificate and its authority chain as a PKCS #12
+// archive, along with the private key when it is not nil. Without a private
+// key the certificates are stored as trusted certificate entries, the only
+// form Java and Windows accept for a key-less archive.
+func encodePKCS12(key crypto.Signer, cert *x509.Certificate, chain []*x509.Certificate, password string) ([]byte, error) {
+	if key == nil {
+		return pkcs12.Modern.EncodeTrustStore(append([]*x509.Certificate{cert}, chain...), password)
+	}
+	return pkcs12.Modern.Encode(key, cert, chain, password)
+}
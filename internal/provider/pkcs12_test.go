@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestEncodePKCS12(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+	key, leaf := testIssuedCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "leaf"}}, caKey, ca)
+
+	t.Run("with private key", func(t *testing.T) {
+		pfx, err := encodePKCS12(key, leaf, []*x509.Certificate{ca}, "secret")
+		require.NoError(t, err)
+		gotKey, gotCert, gotChain, err := pkcs12.DecodeChain(pfx, "secret")
+		require.NoError(t, err)
+		require.True(t, key.(*ecdsa.PrivateKey).Equal(gotKey))
+		require.True(t, leaf.Equal(gotCert))
+		require.Len(t, gotChain, 1)
+		require.True(t, ca.Equal(gotChain[0]))
+	})
+	t.Run("without private key", func(t *testing.T) {
+		pfx, err := encodePKCS12(nil, leaf, []*x509.Certificate{ca}, "")
+		require.NoError(t, err)
+		got, err := pkcs12.DecodeTrustStore(pfx, "")
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.True(t, leaf.Equal(got[0]))
+		require.True(t, ca.Equal(got[1]))
+	})
+}
+
+func testSelfSignedCertificate(t *testing.T, cn string) (crypto.Signer, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return key, cert
+}
+
+// testIssuedCertificate returns a certificate issued by parent from tmpl,
+// whose serial number and validity default to 2 and the next hour. Its key
+// is the public key of tmpl when set, no private key being returned, and a
+// new ECDSA key otherwise. Certificate authorities get the certificate
+// signing key usage unless tmpl sets key usages.
+func testIssuedCertificate(t *testing.T, tmpl *x509.Certificate, parentKey crypto.Signer, parent *x509.Certificate) (crypto.Signer, *x509.Certificate) {
+	t.Helper()
+	if tmpl.SerialNumber == nil {
+		tmpl.SerialNumber = big.NewInt(2)
+	}
+	if tmpl.NotBefore.IsZero() {
+		tmpl.NotBefore = time.Now()
+	}
+	if tmpl.NotAfter.IsZero() {
+		tmpl.NotAfter = time.Now().Add(time.Hour)
+	}
+	if tmpl.IsCA {
+		tmpl.BasicConstraintsValid = true
+		if tmpl.KeyUsage == 0 {
+			tmpl.KeyUsage = x509.KeyUsageCertSign
+		}
+	}
+	var key crypto.Signer
+	pub := tmpl.PublicKey
+	if pub == nil {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		key, pub = k, k.Public()
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return key, cert
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	defaultRetryMaxAttempts       = 3
+	defaultRetryInterval          = 5 * time.Second
+	defaultRetryBackoffMultiplier = 2
+	defaultRetryMaxInterval       = time.Minute
+)
+
+// retryPolicy controls how requests failing with transient errors, such as
+// no EZCA endpoint being reachable, are retried.
+type retryPolicy struct {
+	// maxAttempts is the number of times a request is attempted against all
+	// the endpoints, 1 to not retry.
+	maxAttempts int
+	// interval is the time waited after the first attempt.
+	interval time.Duration
+	// multiplier is the factor the wait grows by after every attempt.
+	multiplier float64
+	// maxInterval bounds the time waited between attempts, 0 for no limit.
+	maxInterval time.Duration
+	// timeout limits the time of a request to a single endpoint, 0 for no
+	// limit.
+	timeout time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts: defaultRetryMaxAttempts,
+	interval:    defaultRetryInterval,
+	multiplier:  defaultRetryBackoffMultiplier,
+	maxInterval: defaultRetryMaxInterval,
+}
+
+// RetryAttributeModel describes the retry attribute data model.
+type RetryAttributeModel struct {
+	MaxAttempts       types.Int64   `tfsdk:"max_attempts"`
+	Interval          types.String  `tfsdk:"interval"`
+	BackoffMultiplier types.Float64 `tfsdk:"backoff_multiplier"`
+	MaxInterval       types.String  `tfsdk:"max_interval"`
+}
+
+// wait returns the time to wait after the attempt, counted from 1.
+func (p retryPolicy) wait(attempt int) time.Duration {
+	w := float64(p.interval)
+	if p.multiplier > 1 {
+		w *= math.Pow(p.multiplier, float64(attempt-1))
+	}
+	if p.maxInterval > 0 && w > float64(p.maxInterval) {
+		return p.maxInterval
+	}
+	return time.Duration(w)
+}
+
+// override returns the policy with the settings of the retry attribute and
+// request timeout that are set replacing its own.
+func (p retryPolicy) override(ctx context.Context, retry types.Object, requestTimeout types.String) (retryPolicy, error) {
+	if !retry.IsNull() && !retry.IsUnknown() {
+		attrs := retry.Attributes()
+		if v, ok := attrs["max_attempts"].(types.Int64); ok && !v.IsNull() && !v.IsUnknown() {
+			if v.ValueInt64() < 1 {
+				return p, fmt.Errorf("invalid retry max attempts %d: must be at least 1", v.ValueInt64())
+			}
+			p.maxAttempts = int(v.ValueInt64())
+		}
+		if v, ok := attrs["interval"].(types.String); ok && !v.IsNull() && !v.IsUnknown() {
+			d, err := time.ParseDuration(v.ValueString())
+			if err != nil {
+				return p, fmt.Errorf("invalid retry interval: %w", err)
+			}
+			p.interval = d
+		}
+		if v, ok := attrs["backoff_multiplier"].(types.Float64); ok && !v.IsNull() && !v.IsUnknown() {
+			if v.ValueFloat64() < 1 {
+				return p, fmt.Errorf("invalid retry backoff multiplier %v: must be at least 1", v.ValueFloat64())
+			}
+			p.multiplier = v.ValueFloat64()
+		}
+		if v, ok := attrs["max_interval"].(types.String); ok && !v.IsNull() && !v.IsUnknown() {
+			d, err := time.ParseDuration(v.ValueString())
+			if err != nil {
+				return p, fmt.Errorf("invalid retry max interval: %w", err)
+			}
+			p.maxInterval = d
+		}
+	}
+	if !requestTimeout.IsNull() && !requestTimeout.IsUnknown() {
+		d, err := time.ParseDuration(requestTimeout.ValueString())
+		if err != nil {
+			return p, fmt.Errorf("invalid request timeout: %w", err)
+		}
+		p.timeout = d
+	}
+	return p, nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+)
+
+// oidExtensionSCTList is the extension of the signed certificate timestamps
+// embedded by certificate transparency logs, RFC 6962 section 3.3.
+var oidExtensionSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// signedCertificateTimestampList returns the TLS encoded
+// SignedCertificateTimestampList embedded in the certificate, nil when it has
+// none.
+func signedCertificateTimestampList(cert *x509.Certificate) []byte {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidExtensionSCTList) {
+			continue
+		}
+		var list []byte
+		if _, err := asn1.Unmarshal(ext.Value, &list); err != nil {
+			return nil
+		}
+		return list
+	}
+	return nil
+}
+
+// signedCertificateTimestampCount returns the number of signed certificate
+// timestamps of the TLS encoded list, 0 when it is malformed.
+func signedCertificateTimestampCount(list []byte) int {
+	if len(list) < 2 || int(binary.BigEndian.Uint16(list)) != len(list)-2 {
+		return 0
+	}
+	n := 0
+	for rest := list[2:]; len(rest) > 0; n++ {
+		if len(rest) < 2 {
+			return 0
+		}
+		l := int(binary.BigEndian.Uint16(rest))
+		if l == 0 || len(rest) < 2+l {
+			return 0
+		}
+		rest = rest[2+l:]
+	}
+	return n
+}
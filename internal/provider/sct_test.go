@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedCertificateTimestamps(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+	require.Nil(t, signedCertificateTimestampList(ca))
+
+	list := []byte{0x00, 0x0a, 0x00, 0x03, 0x01, 0x02, 0x03, 0x00, 0x03, 0x04, 0x05, 0x06}
+	value, err := asn1.Marshal(list)
+	require.NoError(t, err)
+	_, cert := testIssuedCertificate(t, &x509.Certificate{
+		ExtraExtensions: []pkix.Extension{{Id: oidExtensionSCTList, Value: value}},
+	}, caKey, ca)
+
+	require.Equal(t, list, signedCertificateTimestampList(cert))
+	require.Equal(t, 2, signedCertificateTimestampCount(list))
+	require.Equal(t, 0, signedCertificateTimestampCount(list[:5]))
+	require.Equal(t, 0, signedCertificateTimestampCount([]byte{0x00, 0x00}))
+	require.Equal(t, 0, signedCertificateTimestampCount(nil))
+
+	m := KeytosEzcaSslLeafCertResourceModel{RequireSCT: types.BoolValue(true)}
+	saveSCTList(&m, cert)
+	var diags diag.Diagnostics
+	checkSCTs(&m, &diags)
+	require.Empty(t, diags)
+
+	saveSCTList(&m, ca)
+	require.True(t, m.SCTListBase64.IsNull())
+	checkSCTs(&m, &diags)
+	require.True(t, diags.HasError())
+}
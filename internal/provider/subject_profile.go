@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+const (
+	subjectProfileNone   = "none"
+	subjectProfileBasic  = "basic"
+	subjectProfileStrict = "strict"
+)
+
+// iso3166Alpha2 holds the officially assigned ISO 3166-1 alpha-2 country
+// codes.
+var iso3166Alpha2 = func() map[string]bool {
+	codes := map[string]bool{}
+	for _, c := range strings.Fields(`
+		AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ
+		BL BM BN BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR
+		CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR
+		GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU
+		ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ
+		LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ
+		MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF
+		PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI
+		SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO TR
+		TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`) {
+		codes[c] = true
+	}
+	return codes
+}()
+
+// subjectAttributeMaxLengths holds the upper bounds of the subject name
+// attributes defined in RFC 5280 appendix A.1.
+var subjectAttributeMaxLengths = map[string]int{
+	"common_name":         64,
+	"organization":        64,
+	"organizational_unit": 64,
+	"locality":            128,
+	"province":            128,
+	"street_address":      128,
+	"postal_code":         40,
+}
+
+// checkSubjectNameProfile validates the overwrite_subject_name object against
+// the subject validation profile. The basic profile requires ISO 3166-1
+// alpha-2 country codes. The strict profile also requires a single country,
+// an organization when an organizational unit is set, and non-empty values
+// within the RFC 5280 upper bounds.
+func checkSubjectNameProfile(ctx context.Context, profile string, subjectName types.Object, diags *diag.Diagnostics) {
+	if profile == "" || profile == subjectProfileNone || subjectName.IsNull() || subjectName.IsUnknown() {
+		return
+	}
+	var snm SubjectNameAttributeModel
+	diags.Append(subjectName.As(ctx, &snm, basetypes.ObjectAsOptions{UnhandledUnknownAsEmpty: true})...)
+	if diags.HasError() {
+		return
+	}
+	root := path.Root("overwrite_subject_name")
+
+	countries := listStringValues(snm.Country)
+	for i, c := range countries {
+		if !c.IsUnknown() && !iso3166Alpha2[c.ValueString()] {
+			diags.AddAttributeError(
+				root.AtName("country").AtListIndex(i),
+				"Invalid Subject Country",
+				fmt.Sprintf("Country %q is not an upper case ISO 3166-1 alpha-2 code, such as \"US\", as required by the %s subject validation profile.", c.ValueString(), profile),
+			)
+		}
+	}
+	if profile != subjectProfileStrict {
+		return
+	}
+
+	if len(countries) > 1 {
+		diags.AddAttributeError(root.AtName("country"), "Invalid Subject Country", "The strict subject validation profile allows a single country.")
+	}
+	if len(snm.OrganizationalUnit.Elements()) > 0 && len(snm.Organization.Elements()) == 0 {
+		diags.AddAttributeError(root.AtName("organization"), "Missing Subject Organization", "The strict subject validation profile requires an organization when an organizational unit is set.")
+	}
+	values := map[string][]types.String{
+		"common_name":         {snm.CommonName},
+		"organization":        listStringValues(snm.Organization),
+		"organizational_unit": listStringValues(snm.OrganizationalUnit),
+		"locality":            listStringValues(snm.Locality),
+		"province":            listStringValues(snm.Province),
+		"street_address":      listStringValues(snm.StreetAddress),
+		"postal_code":         listStringValues(snm.PostalCode),
+	}
+	for name, vs := range values {
+		for i, v := range vs {
+			if v.IsNull() || v.IsUnknown() {
+				continue
+			}
+			p := root.AtName(name)
+			if name != "common_name" {
+				p = p.AtListIndex(i)
+			}
+			if strings.TrimSpace(v.ValueString()) == "" {
+				diags.AddAttributeError(p, "Invalid Subject Attribute", "The strict subject validation profile does not allow empty values.")
+			} else if maxLen := subjectAttributeMaxLengths[name]; len([]rune(v.ValueString())) > maxLen {
+				diags.AddAttributeError(p, "Invalid Subject Attribute", fmt.Sprintf("Value is longer than the %d characters allowed by RFC 5280.", maxLen))
+			}
+		}
+	}
+}
+
+func listStringValues(l types.List) []types.String {
+	vs := make([]types.String, 0, len(l.Elements()))
+	for _, e := range l.Elements() {
+		if s, ok := e.(types.String); ok {
+			vs = append(vs, s)
+		}
+	}
+	return vs
+}
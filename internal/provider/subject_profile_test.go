@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestISO3166Alpha2(t *testing.T) {
+	require.Len(t, iso3166Alpha2, 249)
+}
+
+func TestCheckSubjectNameProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		subject map[string][]string
+		errors  int
+	}{
+		{name: "none", profile: subjectProfileNone, subject: map[string][]string{"country": {"USA"}}},
+		{name: "basic valid", profile: subjectProfileBasic, subject: map[string][]string{"country": {"US"}}},
+		{name: "basic lower case", profile: subjectProfileBasic, subject: map[string][]string{"country": {"us"}}, errors: 1},
+		{name: "basic alpha-3", profile: subjectProfileBasic, subject: map[string][]string{"country": {"USA"}}, errors: 1},
+		{name: "basic allows unit alone", profile: subjectProfileBasic, subject: map[string][]string{"organizational_unit": {"IT"}}},
+		{name: "strict valid", profile: subjectProfileStrict, subject: map[string][]string{"country": {"US"}, "organization": {"Example"}, "organizational_unit": {"IT"}}},
+		{name: "strict unit without organization", profile: subjectProfileStrict, subject: map[string][]string{"organizational_unit": {"IT"}}, errors: 1},
+		{name: "strict several countries", profile: subjectProfileStrict, subject: map[string][]string{"country": {"US", "CA"}}, errors: 1},
+		{name: "strict empty value", profile: subjectProfileStrict, subject: map[string][]string{"locality": {" "}}, errors: 1},
+		{name: "strict too long", profile: subjectProfileStrict, subject: map[string][]string{"postal_code": {strings.Repeat("1", 41)}}, errors: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			checkSubjectNameProfile(context.Background(), tt.profile, testSubjectNameObject(tt.subject), &diags)
+			require.Equal(t, tt.errors, diags.ErrorsCount(), "%v", diags)
+		})
+	}
+}
+
+func testSubjectNameObject(lists map[string][]string) types.Object {
+	attrs := map[string]attr.Value{"common_name": types.StringNull()}
+	for _, name := range []string{"country", "organization", "organizational_unit", "locality", "province", "street_address", "postal_code"} {
+		if vs, ok := lists[name]; ok {
+			elems := make([]attr.Value, 0, len(vs))
+			for _, v := range vs {
+				elems = append(elems, types.StringValue(v))
+			}
+			attrs[name] = types.ListValueMust(types.StringType, elems)
+		} else {
+			attrs[name] = types.ListNull(types.StringType)
+		}
+	}
+	attrTypes := map[string]attr.Type{"common_name": types.StringType}
+	for name := range attrs {
+		if name != "common_name" {
+			attrTypes[name] = types.ListType{ElemType: types.StringType}
+		}
+	}
+	return types.ObjectValueMust(attrTypes, attrs)
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUUIDValidator(t *testing.T) {
+	tests := []struct {
+		value   types.String
+		invalid bool
+	}{
+		{value: types.StringValue("6e2b4a8c-4c1f-4a4e-9d2b-3f5e7c9a1b2d")},
+		{value: types.StringValue("{6E2B4A8C-4C1F-4A4E-9D2B-3F5E7C9A1B2D}")},
+		{value: types.StringNull()},
+		{value: types.StringUnknown()},
+		{value: types.StringValue(""), invalid: true},
+		{value: types.StringValue("authority"), invalid: true},
+		{value: types.StringValue("6e2b4a8c-4c1f-4a4e-9d2b-3f5e7c9a1b2"), invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value.String(), func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("authority_id"), ConfigValue: tt.value}
+			var resp validator.StringResponse
+			uuidValidator{}.ValidateString(context.Background(), req, &resp)
+			require.Equal(t, tt.invalid, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			if tt.invalid {
+				require.Equal(t, path.Root("authority_id"), resp.Diagnostics[0].(diag.DiagnosticWithPath).Path())
+			}
+		})
+	}
+}
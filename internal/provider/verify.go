@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/markeytos/ezca-go"
+)
+
+var oidExtensionExtendedKeyUsage = asn1.ObjectIdentifier{2, 5, 29, 37}
+
+// x509KeyUsages maps the EZCA key usages to their certificate bits.
+var x509KeyUsages = map[ezca.KeyUsage]x509.KeyUsage{
+	ezca.KeyUsageDigitalSignature: x509.KeyUsageDigitalSignature,
+	ezca.KeyUsageKeyEncipherment:  x509.KeyUsageKeyEncipherment,
+	ezca.KeyUsageDataEncipherment: x509.KeyUsageDataEncipherment,
+	ezca.KeyUsageKeyAgreement:     x509.KeyUsageKeyAgreement,
+	ezca.KeyUsageNonRepudiation:   x509.KeyUsageContentCommitment,
+}
+
+// verifyIssuedCertificate warns about the differences between the
+// certificate issued and the certificate request and sign options it was
+// requested with, such as subject alternative names or extended key usages
+// the authority dropped.
+func verifyIssuedCertificate(csrDER []byte, opts *ezca.SignOptions, cert *x509.Certificate, diags *diag.Diagnostics) {
+	lintIssuedCertificate(cert, diags)
+
+	req, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		diags.AddWarning("Issued Certificate Not Verified", fmt.Sprintf("Error parsing certificate request: %v", err))
+		return
+	}
+	if differences := issuedCertificateDifferences(req, opts, cert); len(differences) > 0 {
+		diags.AddWarning(
+			"Issued Certificate Differs From Request",
+			fmt.Sprintf("The certificate with serial number %s was not issued as requested, the authority template may have dropped or rewritten parts of the request:\n- %s", cert.SerialNumber, strings.Join(differences, "\n- ")),
+		)
+	}
+}
+
+// lintIssuedCertificate warns about the violations of the default issuance
+// policy by the certificate issued, such as SHA-1 signatures or small RSA
+// keys, which usually come from a misconfigured authority template.
+func lintIssuedCertificate(cert *x509.Certificate, diags *diag.Diagnostics) {
+	vs := lintPolicy(cert).checkCertificate(cert)
+	if len(vs) == 0 {
+		return
+	}
+	messages := make([]string, 0, len(vs))
+	for _, v := range vs {
+		messages = append(messages, fmt.Sprintf("%s (%s)", v.message, v.rule))
+	}
+	diags.AddWarning(
+		"Issued Certificate Violates Issuance Policy",
+		fmt.Sprintf("The certificate with serial number %s does not satisfy the default issuance policy, check the configuration of the authority template:\n- %s", cert.SerialNumber, strings.Join(messages, "\n- ")),
+	)
+}
+
+// issuedCertificateDifferences returns the descriptions of the differences
+// between the certificate issued and what was requested.
+func issuedCertificateDifferences(req *x509.CertificateRequest, opts *ezca.SignOptions, cert *x509.Certificate) []string {
+	var differences []string
+
+	if k, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !k.Equal(req.PublicKey) {
+		differences = append(differences, "public key does not match the certificate request")
+	}
+
+	for _, n := range slices.Concat(req.DNSNames, opts.DNSNames) {
+		if !slices.ContainsFunc(cert.DNSNames, func(c string) bool { return strings.EqualFold(c, n) }) {
+			differences = append(differences, fmt.Sprintf("DNS name %q is missing", n))
+		}
+	}
+	for _, e := range slices.Concat(req.EmailAddresses, opts.EmailAddresses) {
+		if !slices.ContainsFunc(cert.EmailAddresses, func(c string) bool { return strings.EqualFold(c, e) }) {
+			differences = append(differences, fmt.Sprintf("email address %q is missing", e))
+		}
+	}
+	for _, ip := range slices.Concat(req.IPAddresses, opts.IPAddresses) {
+		if !slices.ContainsFunc(cert.IPAddresses, ip.Equal) {
+			differences = append(differences, fmt.Sprintf("IP address %s is missing", ip))
+		}
+	}
+	for _, u := range slices.Concat(req.URIs, opts.URIs) {
+		if !slices.ContainsFunc(cert.URIs, func(c *url.URL) bool { return c.String() == u.String() }) {
+			differences = append(differences, fmt.Sprintf("URI %q is missing", u))
+		}
+	}
+
+	keyUsages := opts.KeyUsages
+	if len(keyUsages) == 0 {
+		keyUsages = []ezca.KeyUsage{ezca.KeyUsageKeyEncipherment, ezca.KeyUsageDigitalSignature}
+	}
+	for _, ku := range keyUsages {
+		if bit, ok := x509KeyUsages[ku]; ok && cert.KeyUsage&bit == 0 {
+			differences = append(differences, fmt.Sprintf("key usage %q is missing", ku))
+		}
+	}
+
+	ekus := opts.ExtendedKeyUsages
+	if len(ekus) == 0 {
+		ekus = []ezca.ExtKeyUsage{ezca.ExtKeyUsageServerAuth, ezca.ExtKeyUsageClientAuth}
+	}
+	issued := extendedKeyUsageOIDs(cert.Extensions)
+	for _, eku := range ekus {
+		if !slices.Contains(issued, string(eku)) {
+			differences = append(differences, fmt.Sprintf("extended key usage %s is missing", eku))
+		}
+	}
+	for _, eku := range issued {
+		if !slices.Contains(ekus, ezca.ExtKeyUsage(eku)) {
+			differences = append(differences, fmt.Sprintf("extended key usage %s was added", eku))
+		}
+	}
+
+	return differences
+}
+
+// extendedKeyUsageOIDs returns the extended key usages of the certificate or
+// certificate request extensions in dotted notation.
+func extendedKeyUsageOIDs(extensions []pkix.Extension) []string {
+	var oids []string
+	for _, ext := range extensions {
+		if !ext.Id.Equal(oidExtensionExtendedKeyUsage) {
+			continue
+		}
+		var ekus []asn1.ObjectIdentifier
+		if _, err := asn1.Unmarshal(ext.Value, &ekus); err != nil {
+			return nil
+		}
+		for _, eku := range ekus {
+			oids = append(oids, eku.String())
+		}
+	}
+	return oids
+}
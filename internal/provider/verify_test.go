@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) 2025 Keytos
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"net"
+	"testing"
+
+	"github.com/markeytos/ezca-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssuedCertificateDifferences(t *testing.T) {
+	caKey, ca := testSelfSignedCertificate(t, "Test CA")
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: []string{"test.com"}}, key)
+	require.NoError(t, err)
+	req, err := x509.ParseCertificateRequest(csrDER)
+	require.NoError(t, err)
+	opts := &ezca.SignOptions{
+		DNSNames:          []string{"www.test.com"},
+		IPAddresses:       []net.IP{net.ParseIP("10.0.0.1")},
+		ExtendedKeyUsages: []ezca.ExtKeyUsage{ezca.ExtKeyUsageServerAuth},
+	}
+
+	issue := func(pub any, tmpl *x509.Certificate) *x509.Certificate {
+		tmpl.PublicKey = pub
+		_, cert := testIssuedCertificate(t, tmpl, caKey, ca)
+		return cert
+	}
+
+	cert := issue(key.Public(), &x509.Certificate{
+		DNSNames:    []string{"TEST.com", "www.test.com"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	require.Empty(t, issuedCertificateDifferences(req, opts, cert))
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	cert = issue(otherKey.Public(), &x509.Certificate{
+		DNSNames:    []string{"test.com"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	})
+	require.Equal(t, []string{
+		"public key does not match the certificate request",
+		`DNS name "www.test.com" is missing`,
+		"IP address 10.0.0.1 is missing",
+		`key usage "Key Encipherment" is missing`,
+		"extended key usage 1.3.6.1.5.5.7.3.2 was added",
+	}, issuedCertificateDifferences(req, opts, cert))
+}